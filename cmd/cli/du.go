@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	"github.com/spf13/cobra"
+)
+
+func NewDuCommand() *cobra.Command {
+	opts := &filesystemOptions{}
+	usageOpts := vfsadapter.UsageOptions{}
+	var maxDepth int
+	var summarize, all, human bool
+
+	cmd := &cobra.Command{
+		Use:   "du [flags] [vfs-path...]",
+		Short: "Show disk usage in the vfs",
+		Long:  `Sum the size of all files below each path and print the total of every directory, largest first, like the disk usage view of the TUI.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			roots := args
+			if len(roots) == 0 {
+				roots = []string{"/"}
+			}
+			if summarize {
+				maxDepth = 0
+			}
+
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
+			}
+
+			adapter := vfsadapter.New(ctx, fs)
+			format := func(size int64) string {
+				if human {
+					return humanize.IBytes(uint64(size))
+				}
+				return fmt.Sprintf("%d", size)
+			}
+
+			for _, root := range roots {
+				tree, usageErr := adapter.DiskUsage(root, usageOpts, nil)
+				if usageErr != nil {
+					err = fmt.Errorf("failed to read usage of '%s': %v", root, usageErr)
+					break
+				}
+				printUsage(tree, 0, maxDepth, all, format)
+			}
+
+			// Shutdown up VFS mounts before exiting
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
+			}
+
+			return err
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().IntVarP(&maxDepth, "max-depth", "d", -1, "only print totals for directories this far below each path (-1 for no limit)")
+	cmd.Flags().BoolVarP(&summarize, "summarize", "s", false, "only print the total of each path")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "print files as well as directories")
+	cmd.Flags().BoolVarP(&human, "human-readable", "H", false, "print sizes like 1.5 MiB")
+	cmd.Flags().BoolVarP(&usageOpts.OneFileSystem, "one-file-system", "x", false, "skip mount points below each path")
+
+	return cmd
+}
+
+// printUsage prints the totals of node and its children, largest first, up to maxDepth
+func printUsage(node *vfsadapter.UsageNode, depth, maxDepth int, all bool, format func(int64) string) {
+	if !node.Entry.IsDir && !all && depth > 0 {
+		return
+	}
+
+	if node.Err != nil {
+		fmt.Fprintf(os.Stderr, "cannot read '%s': %v\n", node.Entry.Path, node.Err)
+	}
+	fmt.Printf("%s\t%s\n", format(node.Size), node.Entry.Path)
+
+	if maxDepth >= 0 && depth >= maxDepth {
+		return
+	}
+	for _, child := range node.Children {
+		printUsage(child, depth+1, maxDepth, all, format)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfsh/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+// ExitError reports a non-zero exit code from a vfs command
+type ExitError struct {
+	Code int
+	Err  error // Error the command failed with, nil if it only exited non-zero
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("command failed with code %d: %v", e.Code, e.Err)
+	}
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func NewExecCommand() *cobra.Command {
+	opts := &filesystemOptions{}
+	var scriptPath string
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] [-- command [args...]]",
+		Short: "Execute vfs commands",
+		Long:  `Execute a single vfs command or a script of commands without starting the terminal user interface.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			if scriptPath == "" && len(args) == 0 {
+				return fmt.Errorf("either a command or a script file (-f) is required")
+			}
+			if scriptPath != "" && len(args) > 0 {
+				return fmt.Errorf("a command and a script file (-f) cannot be used together")
+			}
+
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
+			}
+
+			var exitCode int
+			if scriptPath != "" {
+				exitCode, err = executeScript(ctx, fs, scriptPath)
+			} else {
+				exitCode, err = executeCommand(ctx, fs, args)
+			}
+
+			// Shutdown up VFS mounts before exiting
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
+			}
+
+			if err != nil {
+				return err
+			}
+			if exitCode != 0 {
+				return &ExitError{Code: exitCode}
+			}
+
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().StringVarP(&scriptPath, "file", "f", "", "script file with one command per line ('-' reads from stdin)")
+	// Everything after the first argument belongs to the vfs command
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// executeCommand runs a single command and streams its output to stdout.
+// A command that fails returns an ExitError with at least exit code 1.
+func executeCommand(ctx context.Context, fs vfs.VirtualFileSystem, args []string) (int, error) {
+	exitCode, err := fs.Execute(ctx, os.Stdout, args...)
+	if err != nil {
+		if exitCode == 0 {
+			exitCode = 1
+		}
+		return exitCode, &ExitError{Code: exitCode, Err: err}
+	}
+
+	return exitCode, nil
+}
+
+// executeScript runs every line of a script and stops at the first failing command
+func executeScript(ctx context.Context, fs vfs.VirtualFileSystem, scriptPath string) (int, error) {
+	var reader io.Reader = os.Stdin
+	if scriptPath != "-" {
+		file, err := os.Open(scriptPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open script: %v", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args := shell.ParseCommandLine(line)
+		if len(args) == 0 {
+			continue
+		}
+
+		exitCode, err := executeCommand(ctx, fs, args)
+		if err != nil {
+			return exitCode, fmt.Errorf("%s:%d: %w", scriptPath, lineNumber, err)
+		}
+		if exitCode != 0 {
+			fmt.Fprintf(os.Stderr, "%s:%d: '%s' exited with code %d\n", scriptPath, lineNumber, line, exitCode)
+			return exitCode, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read script: %v", err)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	"github.com/spf13/cobra"
+)
+
+func NewGrepCommand() *cobra.Command {
+	opts := &filesystemOptions{}
+	searchOpts := vfsadapter.SearchOptions{}
+	var filesOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "grep [flags] <pattern> [vfs-path...]",
+		Short: "Search file contents in the vfs",
+		Long:  `Search text files in the vfs for a literal string or regular expression and print every match as path:line:snippet. Binary files are skipped.`,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			searchOpts.Pattern = args[0]
+			roots := args[1:]
+			if len(roots) == 0 {
+				roots = []string{"/"}
+			}
+
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
+			}
+
+			adapter := vfsadapter.New(ctx, fs)
+			matches := 0
+
+			for _, root := range roots {
+				printed := make(map[string]bool)
+				summary, searchErr := adapter.Search(root, searchOpts, nil, func(match vfsadapter.SearchMatch) {
+					if !filesOnly {
+						fmt.Println(match)
+					} else if !printed[match.Path] {
+						printed[match.Path] = true
+						fmt.Println(match.Path)
+					}
+				})
+				matches += summary.Matches
+
+				if searchErr != nil {
+					err = fmt.Errorf("failed to search '%s': %v", root, searchErr)
+					break
+				}
+			}
+
+			// Shutdown up VFS mounts before exiting
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
+			}
+
+			if err != nil {
+				return err
+			}
+			// Like grep, exit with 1 if nothing matched
+			if matches == 0 {
+				return &ExitError{Code: 1}
+			}
+
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().BoolVarP(&searchOpts.Regex, "regexp", "E", false, "treat the pattern as regular expression")
+	cmd.Flags().BoolVarP(&searchOpts.IgnoreCase, "ignore-case", "i", false, "ignore case when matching")
+	cmd.Flags().IntVarP(&searchOpts.MaxPerFile, "max-count", "m", 0, "stop reading a file after this many matches (0 for no limit)")
+	cmd.Flags().BoolVarP(&filesOnly, "files-with-matches", "l", false, "only print the paths of matching files")
+
+	return cmd
+}
@@ -10,34 +10,117 @@ import (
 	"github.com/mwantia/vfs/mount"
 	"github.com/mwantia/vfs/mount/backend/ephemeral"
 	"github.com/mwantia/vfs/mount/backend/sqlite"
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/spf13/cobra"
 )
 
-func initializeVirtualFileSystem(ctx context.Context, configPath string) (vfs.VirtualFileSystem, error) {
-	logPath := filepath.Join(configPath, "vfsh.log")
+// filesystemOptions holds the flags shared by all commands that operate on the vfs
+type filesystemOptions struct {
+	configPath  string
+	demoEnabled bool
 
-	fs, err := vfs.NewVirtualFileSystem(vfs.WithLogFile(logPath), vfs.WithoutTerminalLog())
+	mounts *config.MountConfig // Mounts loaded by setup
+}
+
+// addFlags registers the shared filesystem flags on cmd
+func (o *filesystemOptions) addFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&o.configPath, "config", "", "config path (default: ~/.config/vfsh)")
+	cmd.PersistentFlags().BoolVar(&o.demoEnabled, "demo", false, "creates a /demo mount if enabled (default: false)")
+}
+
+// setup resolves the config directory and initializes the vfs from it
+func (o *filesystemOptions) setup(ctx context.Context) (vfs.VirtualFileSystem, error) {
+	if o.configPath == "" {
+		path, err := config.GetConfigDirectory()
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup vfs: %v", err)
+		}
+		o.configPath = path
+	}
+
+	cfg, err := config.LoadMountConfig(o.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup vfs: %v", err)
 	}
+	o.mounts = cfg
 
-	rootPath := filepath.Join(configPath, "vfsh.db")
-	root, err := sqlite.NewSQLiteBackend(rootPath)
+	fs, err := initializeVirtualFileSystem(ctx, o.configPath, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup vfs: %v", err)
+		return nil, fmt.Errorf("failed to initialize vfs: %v", err)
 	}
 
-	if err := fs.Mount(ctx, "/", root, mount.WithMetadata(root), mount.WithNamespace("root")); err != nil {
-		return nil, fmt.Errorf("failed to setup vfs: %v", err)
+	if o.demoEnabled {
+		if err := initializeDemo(ctx, fs); err != nil {
+			// Release the mounts that are already set up
+			_ = fs.Shutdown(ctx)
+			return nil, fmt.Errorf("failed to initialize vfs: %v", err)
+		}
 	}
 
-	ephemeral := ephemeral.NewEphemeralBackend()
-	if err := fs.Mount(ctx, "/ephemeral", ephemeral); err != nil {
+	return fs, nil
+}
+
+func initializeVirtualFileSystem(ctx context.Context, configPath string, cfg *config.MountConfig) (vfs.VirtualFileSystem, error) {
+	logPath := filepath.Join(configPath, "vfsh.log")
+
+	fs, err := vfs.NewVirtualFileSystem(vfs.WithLogFile(logPath), vfs.WithoutTerminalLog())
+	if err != nil {
 		return nil, fmt.Errorf("failed to setup vfs: %v", err)
 	}
 
+	// All other mounts are placed into the root mount, so it is mounted first
+	mounts := make([]*config.MountEntry, 0, len(cfg.Mounts))
+	for _, entry := range cfg.Mounts {
+		if entry.Path == "/" {
+			mounts = append([]*config.MountEntry{entry}, mounts...)
+		} else {
+			mounts = append(mounts, entry)
+		}
+	}
+
+	for _, entry := range mounts {
+		if err := mountEntry(ctx, fs, configPath, entry); err != nil {
+			// Release the mounts that are already set up
+			_ = fs.Shutdown(ctx)
+			return nil, fmt.Errorf("failed to setup vfs: %v", err)
+		}
+	}
+
 	return fs, nil
 }
 
+// mountEntry creates the backend described by entry and mounts it into fs
+func mountEntry(ctx context.Context, fs vfs.VirtualFileSystem, configPath string, entry *config.MountEntry) error {
+	var opts []mount.MountOption
+	if entry.Namespace != "" {
+		opts = append(opts, mount.WithNamespace(entry.Namespace))
+	}
+
+	var err error
+	switch entry.Backend {
+	case config.BackendSQLite:
+		backend, createErr := sqlite.NewSQLiteBackend(entry.ResolvePath(configPath, "path"))
+		if createErr != nil {
+			return fmt.Errorf("failed to create sqlite backend for '%s': %v", entry.Path, createErr)
+		}
+		if entry.Metadata {
+			opts = append(opts, mount.WithMetadata(backend))
+		}
+		err = fs.Mount(ctx, entry.Path, backend, opts...)
+
+	case config.BackendEphemeral:
+		err = fs.Mount(ctx, entry.Path, ephemeral.NewEphemeralBackend(), opts...)
+
+	default:
+		return fmt.Errorf("unknown backend '%s' for '%s'", entry.Backend, entry.Path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to mount '%s': %v", entry.Path, err)
+	}
+	return nil
+}
+
 func initializeDemo(ctx context.Context, fs vfs.VirtualFileSystem) error {
 	demo := ephemeral.NewEphemeralBackend()
 	if err := fs.Mount(ctx, "/demo", demo); err != nil {
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/mwantia/vfsh/internal/repl"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	"github.com/spf13/cobra"
+)
+
+func NewShellCommand() *cobra.Command {
+	opts := &filesystemOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive line-based shell",
+		Long:  `Run the VFS Shell as line-based interactive shell without the full-screen terminal user interface.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
+			}
+
+			adapter := vfsadapter.New(ctx, fs)
+			sh := repl.NewShell(adapter,
+				filepath.Join(opts.configPath, repl.HistoryFileName),
+				filepath.Join(opts.configPath, config.BookmarkFileName))
+
+			if err = sh.Run(); err != nil {
+				err = fmt.Errorf("shell error: %v", err)
+			}
+
+			// Shutdown up VFS mounts before exiting
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
+			}
+
+			return err
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	return cmd
+}
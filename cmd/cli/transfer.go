@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfsh/internal/transfer"
+	"github.com/spf13/cobra"
+)
+
+// transferFunc performs a single transfer between the host and the vfs
+type transferFunc func(ctx context.Context, fs vfs.VirtualFileSystem, src, dst string, opts transfer.Options) (*transfer.Summary, error)
+
+func NewPutCommand() *cobra.Command {
+	return newTransferCommand(
+		"put <host-path> <vfs-path>",
+		"Copy files from the host into the vfs",
+		`Copy a file or directory tree from the host filesystem into the vfs.`,
+		transfer.Put,
+	)
+}
+
+func NewGetCommand() *cobra.Command {
+	return newTransferCommand(
+		"get <vfs-path> <host-path>",
+		"Copy files from the vfs onto the host",
+		`Copy a file or directory tree from the vfs onto the host filesystem.`,
+		transfer.Get,
+	)
+}
+
+func newTransferCommand(use, short, long string, run transferFunc) *cobra.Command {
+	opts := &filesystemOptions{}
+	var dryRun bool
+	var quiet bool
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
+			}
+
+			transferOpts := transfer.Options{
+				DryRun: dryRun,
+			}
+			if !quiet {
+				transferOpts.Progress = os.Stdout
+			}
+
+			summary, err := run(ctx, fs, args[0], args[1], transferOpts)
+			if err == nil {
+				for _, warning := range summary.Warnings {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+				}
+				fmt.Printf("Transferred %s\n", summary)
+			}
+
+			// Shutdown up VFS mounts before exiting
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
+			}
+
+			return err
+		},
+	}
+
+	opts.addFlags(cmd)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only show what would be transferred")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "only print the final summary")
+
+	return cmd
+}
@@ -2,16 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/mwantia/vfsh/internal/config"
 	"github.com/mwantia/vfsh/internal/tui"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
 	"github.com/spf13/cobra"
 )
 
 func NewTuiCommand() *cobra.Command {
-	var configPath string
-	var demoEnabled bool
+	opts := &filesystemOptions{}
+	var graphics string
 
 	cmd := &cobra.Command{
 		Use:   "tui",
@@ -20,45 +21,54 @@ func NewTuiCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			if configPath == "" {
-				path, err := config.GetConfigDirectory()
-				if err != nil {
-					return fmt.Errorf("failed to setup vfs: %v", err)
-				}
-				configPath = path
-			}
-
-			fs, err := initializeVirtualFileSystem(ctx, configPath)
+			protocol, err := tui.ParseGraphicsProtocol(graphics)
 			if err != nil {
-				return fmt.Errorf("failed to initialize vfs: %v", err)
+				return err
 			}
 
-			if demoEnabled {
-				if err := initializeDemo(ctx, fs); err != nil {
-					return fmt.Errorf("failed to initialize vfs: %v", err)
-				}
+			fs, err := opts.setup(ctx)
+			if err != nil {
+				return err
 			}
 
 			// Create VFS adapter and TUI model
-			adapter := tui.NewVFSAdapter(ctx, fs)
-			model := tui.NewModel(adapter)
+			adapter := vfsadapter.New(ctx, fs)
+			model := tui.NewModel(adapter, opts.configPath)
+			model.SetGraphics(protocol)
+
+			// Mounts may bound how much of them the fuzzy finder indexes
+			for _, entry := range opts.mounts.Mounts {
+				model.SetIndexLimit(entry.Path, tui.IndexLimit{
+					MaxDepth:   entry.IndexDepth,
+					MaxEntries: entry.IndexLimit,
+				})
+			}
 
 			p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
-			if _, err := p.Run(); err != nil {
-				return fmt.Errorf("tui error: %v", err)
+			_, err = p.Run()
+
+			// Jobs still running must return before their mounts are shut down
+			model.Close()
+			if err != nil {
+				err = fmt.Errorf("tui error: %v", err)
+			}
+
+			// Restore the open tabs on the next start
+			if saveErr := model.SaveSession(); saveErr != nil {
+				fmt.Fprintf(os.Stderr, "failed to save session: %v\n", saveErr)
 			}
 
 			// Shutdown up VFS mounts before exiting
-			if err := fs.Shutdown(ctx); err != nil {
-				return fmt.Errorf("failed to properly close VFS: %v", err)
+			if shutdownErr := fs.Shutdown(ctx); shutdownErr != nil && err == nil {
+				err = fmt.Errorf("failed to properly close VFS: %v", shutdownErr)
 			}
 
-			return nil
+			return err
 		},
 	}
 
-	cmd.PersistentFlags().StringVar(&configPath, "config", "", "config path (default: ~/.config/vfsh)")
-	cmd.PersistentFlags().BoolVar(&demoEnabled, "demo", false, "creates a /demo mount if enabled (default: false)")
+	opts.addFlags(cmd)
+	cmd.Flags().StringVar(&graphics, "graphics", "auto", "image preview protocol: auto, kitty, sixel, iterm2 or ansi")
 
 	return cmd
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -20,8 +21,23 @@ func main() {
 
 	root.AddCommand(cli.NewVersionCommand())
 	root.AddCommand(cli.NewTuiCommand())
+	root.AddCommand(cli.NewShellCommand())
+	root.AddCommand(cli.NewExecCommand())
+	root.AddCommand(cli.NewPutCommand())
+	root.AddCommand(cli.NewGetCommand())
+	root.AddCommand(cli.NewGrepCommand())
+	root.AddCommand(cli.NewDuCommand())
 
 	if err := root.Execute(); err != nil {
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			// Commands that only exited non-zero have already reported on their own
+			if exitErr.Err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(exitErr.Code)
+		}
+
 		fmt.Println(err)
 		os.Exit(1)
 	}
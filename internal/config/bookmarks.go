@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BookmarkFileName is the name of the bookmark file inside the config directory
+const BookmarkFileName = "bookmarks.yaml"
+
+// Bookmarks maps single-letter names to absolute vfs directories
+type Bookmarks map[string]string
+
+// ValidateBookmarkName checks that name is a single ASCII letter
+func ValidateBookmarkName(name string) error {
+	if len(name) != 1 || !((name[0] >= 'a' && name[0] <= 'z') || (name[0] >= 'A' && name[0] <= 'Z')) {
+		return fmt.Errorf("invalid bookmark '%s': name must be a single letter", name)
+	}
+	return nil
+}
+
+// LoadBookmarks reads the bookmarks from bookmarkPath.
+// If no bookmark file exists, an empty set is returned.
+func LoadBookmarks(bookmarkPath string) (Bookmarks, error) {
+	content, err := os.ReadFile(bookmarkPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Bookmarks{}, nil
+		}
+		return nil, fmt.Errorf("failed to read bookmarks: %v", err)
+	}
+
+	bookmarks := Bookmarks{}
+	if err := yaml.Unmarshal(content, &bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks '%s': %v", bookmarkPath, err)
+	}
+
+	return bookmarks, nil
+}
+
+// SaveBookmarks writes the bookmarks to bookmarkPath
+func SaveBookmarks(bookmarkPath string, bookmarks Bookmarks) error {
+	content, err := yaml.Marshal(bookmarks)
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %v", err)
+	}
+
+	if err := os.WriteFile(bookmarkPath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write bookmarks '%s': %v", bookmarkPath, err)
+	}
+
+	return nil
+}
+
+// SetBookmark stores dir under name. The file is re-read first so
+// bookmarks set concurrently by another vfsh process are kept.
+func SetBookmark(bookmarkPath, name, dir string) error {
+	if err := ValidateBookmarkName(name); err != nil {
+		return err
+	}
+
+	bookmarks, err := LoadBookmarks(bookmarkPath)
+	if err != nil {
+		return err
+	}
+
+	bookmarks[name] = path.Clean(dir)
+	return SaveBookmarks(bookmarkPath, bookmarks)
+}
+
+// LookupBookmark returns the directory stored under name
+func LookupBookmark(bookmarkPath, name string) (string, error) {
+	if err := ValidateBookmarkName(name); err != nil {
+		return "", err
+	}
+
+	bookmarks, err := LoadBookmarks(bookmarkPath)
+	if err != nil {
+		return "", err
+	}
+
+	dir, ok := bookmarks[name]
+	if !ok {
+		return "", fmt.Errorf("bookmark '%s' is not set", name)
+	}
+	return dir, nil
+}
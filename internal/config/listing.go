@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ListingFileName is the name of the TUI listing configuration inside the config directory
+const ListingFileName = "listing.yaml"
+
+const (
+	ColumnSize    string = "size"
+	ColumnModTime string = "mtime"
+	ColumnMode    string = "mode"
+	ColumnType    string = "type"
+)
+
+const (
+	TimeFormatRelative string = "relative"
+	TimeFormatAbsolute string = "absolute"
+)
+
+// ListingConfig describes the columns shown in the long listing of the TUI
+type ListingConfig struct {
+	// Long enables the long listing for new tabs
+	Long bool `yaml:"long,omitempty"`
+	// Columns are shown after the name and dropped from the end when the window is too narrow
+	Columns    []string `yaml:"columns"`
+	TimeFormat string   `yaml:"time_format"`
+}
+
+// DefaultListingConfig returns the listing used when no configuration file exists
+func DefaultListingConfig() *ListingConfig {
+	return &ListingConfig{
+		Columns:    []string{ColumnSize, ColumnModTime, ColumnMode, ColumnType},
+		TimeFormat: TimeFormatRelative,
+	}
+}
+
+// LoadListingConfig reads the listing configuration from listingPath.
+// If no configuration file exists, the default listing is returned.
+func LoadListingConfig(listingPath string) (*ListingConfig, error) {
+	content, err := os.ReadFile(listingPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultListingConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read listing config: %v", err)
+	}
+
+	cfg := DefaultListingConfig()
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse listing config '%s': %v", listingPath, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid listing config '%s': %v", listingPath, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that all columns and the time format are known
+func (c *ListingConfig) Validate() error {
+	for _, column := range c.Columns {
+		switch column {
+		case ColumnSize, ColumnModTime, ColumnMode, ColumnType:
+		default:
+			return fmt.Errorf("unknown column '%s'", column)
+		}
+	}
+
+	switch c.TimeFormat {
+	case TimeFormatRelative, TimeFormatAbsolute:
+	default:
+		return fmt.Errorf("unknown time format '%s'", c.TimeFormat)
+	}
+
+	return nil
+}
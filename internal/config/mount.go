@@ -0,0 +1,171 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MountFileName is the name of the mount configuration file inside the config directory
+const MountFileName = "vfsh.yaml"
+
+const (
+	BackendSQLite    string = "sqlite"
+	BackendEphemeral string = "ephemeral"
+)
+
+// MountConfig describes the mounts that make up the virtual filesystem
+type MountConfig struct {
+	Mounts []*MountEntry `yaml:"mounts"`
+}
+
+// MountEntry describes a single mount within the virtual filesystem
+type MountEntry struct {
+	Path      string            `yaml:"path"`
+	Backend   string            `yaml:"backend"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Metadata  bool              `yaml:"metadata,omitempty"`
+	Options   map[string]string `yaml:"options,omitempty"`
+
+	// Limits for the fuzzy finder index, zero uses the defaults
+	IndexDepth int `yaml:"index_depth,omitempty"`
+	IndexLimit int `yaml:"index_limit,omitempty"`
+}
+
+// DefaultMountConfig returns the mount layout used when no configuration file exists
+func DefaultMountConfig() *MountConfig {
+	return &MountConfig{
+		Mounts: []*MountEntry{
+			{
+				Path:      "/",
+				Backend:   BackendSQLite,
+				Namespace: "root",
+				Metadata:  true,
+				Options: map[string]string{
+					"path": "vfsh.db",
+				},
+			},
+			{
+				Path:    "/ephemeral",
+				Backend: BackendEphemeral,
+			},
+		},
+	}
+}
+
+// LoadMountConfig reads the mount configuration from the config directory.
+// If no configuration file exists, the default mount layout is returned.
+func LoadMountConfig(configPath string) (*MountConfig, error) {
+	filePath := filepath.Join(configPath, MountFileName)
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultMountConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read mount config: %v", err)
+	}
+
+	cfg := &MountConfig{}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse mount config '%s': %v", filePath, err)
+	}
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mount config '%s': %v", filePath, err)
+	}
+
+	return cfg, nil
+}
+
+// normalize cleans the paths of all mounts, so they can be compared and mounted as written
+func (c *MountConfig) normalize() {
+	for _, entry := range c.Mounts {
+		if strings.HasPrefix(entry.Path, "/") {
+			entry.Path = path.Clean(entry.Path)
+		}
+	}
+}
+
+// Validate checks that all mounts are well-formed and do not overlap.
+// Every mount is placed into the root mount, but no mount may be nested inside another one.
+func (c *MountConfig) Validate() error {
+	if len(c.Mounts) == 0 {
+		return fmt.Errorf("no mounts defined")
+	}
+
+	seen := make(map[string]int)
+	for i, entry := range c.Mounts {
+		if entry.Path == "" {
+			return fmt.Errorf("mount #%d: path is required", i+1)
+		}
+		if !strings.HasPrefix(entry.Path, "/") {
+			return fmt.Errorf("mount #%d: path '%s' must be absolute", i+1, entry.Path)
+		}
+
+		mountPath := path.Clean(entry.Path)
+		for prevPath, prev := range seen {
+			if overlaps(mountPath, prevPath) {
+				return fmt.Errorf("mount #%d: path '%s' overlaps with mount #%d at '%s'", i+1, mountPath, prev, prevPath)
+			}
+		}
+		seen[mountPath] = i + 1
+
+		if entry.IndexDepth < 0 || entry.IndexLimit < 0 {
+			return fmt.Errorf("mount #%d: index limits must not be negative", i+1)
+		}
+
+		switch entry.Backend {
+		case BackendSQLite:
+			if entry.Option("path") == "" {
+				return fmt.Errorf("mount #%d: backend '%s' requires option 'path'", i+1, entry.Backend)
+			}
+		case BackendEphemeral:
+			if entry.Metadata {
+				return fmt.Errorf("mount #%d: backend '%s' cannot provide metadata", i+1, entry.Backend)
+			}
+		case "":
+			return fmt.Errorf("mount #%d: backend is required", i+1)
+		default:
+			return fmt.Errorf("mount #%d: unknown backend '%s'", i+1, entry.Backend)
+		}
+	}
+
+	if _, ok := seen["/"]; !ok {
+		return fmt.Errorf("no mount defined for root path '/'")
+	}
+
+	return nil
+}
+
+// Option returns the value of the named backend option
+func (e *MountEntry) Option(name string) string {
+	if e.Options == nil {
+		return ""
+	}
+	return e.Options[name]
+}
+
+// ResolvePath resolves a path option relative to the config directory
+func (e *MountEntry) ResolvePath(configPath, name string) string {
+	value := e.Option(name)
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+	return filepath.Join(configPath, value)
+}
+
+// overlaps reports whether two cleaned mount paths are the same or one lies inside the other.
+// The root mount is the base of the tree and does not overlap with anything else.
+func overlaps(a, b string) bool {
+	if a == "/" || b == "/" {
+		return a == b
+	}
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func sqliteMount(path string) *MountEntry {
+	return &MountEntry{Path: path, Backend: BackendSQLite, Options: map[string]string{"path": "vfsh.db"}}
+}
+
+func ephemeralMount(path string) *MountEntry {
+	return &MountEntry{Path: path, Backend: BackendEphemeral}
+}
+
+func TestMountConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mounts []*MountEntry
+		err    string // Substring of the expected error, empty if valid
+	}{
+		{"default", DefaultMountConfig().Mounts, ""},
+		{"siblings", []*MountEntry{sqliteMount("/"), ephemeralMount("/data"), ephemeralMount("/tmp")}, ""},
+		{"similar prefix", []*MountEntry{sqliteMount("/"), ephemeralMount("/data"), ephemeralMount("/database")}, ""},
+		{"no mounts", nil, "no mounts defined"},
+		{"no root", []*MountEntry{ephemeralMount("/data")}, "no mount defined for root"},
+		{"relative", []*MountEntry{sqliteMount("/"), ephemeralMount("data")}, "must be absolute"},
+		{"empty path", []*MountEntry{sqliteMount("/"), ephemeralMount("")}, "path is required"},
+		{"duplicate", []*MountEntry{sqliteMount("/"), ephemeralMount("/data"), ephemeralMount("/data/")}, "overlaps with mount #2"},
+		{"nested", []*MountEntry{sqliteMount("/"), ephemeralMount("/data"), ephemeralMount("/data/x")}, "overlaps with mount #2"},
+		{"nested first", []*MountEntry{sqliteMount("/"), ephemeralMount("/data/x"), ephemeralMount("/data")}, "overlaps with mount #2"},
+		{"no backend", []*MountEntry{sqliteMount("/"), {Path: "/x"}}, "backend is required"},
+		{"unknown backend", []*MountEntry{sqliteMount("/"), {Path: "/x", Backend: "s3"}}, "unknown backend 's3'"},
+		{"sqlite without file", []*MountEntry{{Path: "/", Backend: BackendSQLite}}, "requires option 'path'"},
+		{"ephemeral metadata", []*MountEntry{sqliteMount("/"), {Path: "/x", Backend: BackendEphemeral, Metadata: true}}, "cannot provide metadata"},
+		{"negative limit", []*MountEntry{sqliteMount("/"), {Path: "/x", Backend: BackendEphemeral, IndexLimit: -1}}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&MountConfig{Mounts: tt.mounts}).Validate()
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && err == nil:
+				t.Fatalf("expected error containing %q", tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Fatalf("error %q does not contain %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMountConfigValidateKeepsPaths(t *testing.T) {
+	cfg := &MountConfig{Mounts: []*MountEntry{sqliteMount("/"), ephemeralMount("/data/")}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Mounts[1].Path; got != "/data/" {
+		t.Fatalf("Validate changed path to %q", got)
+	}
+
+	cfg.normalize()
+	if got := cfg.Mounts[1].Path; got != "/data" {
+		t.Fatalf("normalize returned %q, want /data", got)
+	}
+}
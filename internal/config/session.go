@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SessionFileName is the name of the TUI session file inside the config directory
+const SessionFileName = "session.yaml"
+
+// Session describes the TUI tabs that are restored on the next start
+type Session struct {
+	Tabs      []*SessionTab `yaml:"tabs"`
+	ActiveTab int           `yaml:"active_tab,omitempty"`
+}
+
+// SessionTab describes the directories and layout of a single tab
+type SessionTab struct {
+	Panes       []string `yaml:"panes"`
+	ActivePane  int      `yaml:"active_pane,omitempty"`
+	DualPane    bool     `yaml:"dual_pane,omitempty"`
+	HidePreview bool     `yaml:"hide_preview,omitempty"`
+	LongListing bool     `yaml:"long_listing,omitempty"`
+}
+
+// LoadSession reads the session from sessionPath.
+// If no session file exists, an empty session is returned.
+func LoadSession(sessionPath string) (*Session, error) {
+	content, err := os.ReadFile(sessionPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Session{}, nil
+		}
+		return nil, fmt.Errorf("failed to read session: %v", err)
+	}
+
+	session := &Session{}
+	if err := yaml.Unmarshal(content, session); err != nil {
+		return nil, fmt.Errorf("failed to parse session '%s': %v", sessionPath, err)
+	}
+
+	return session, nil
+}
+
+// SaveSession writes the session to sessionPath
+func SaveSession(sessionPath string, session *Session) error {
+	content, err := yaml.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+
+	if err := os.WriteFile(sessionPath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write session '%s': %v", sessionPath, err)
+	}
+
+	return nil
+}
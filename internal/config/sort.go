@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SortFileName is the name of the listing sort configuration inside the config directory
+const SortFileName = "sorting.yaml"
+
+const (
+	SortByName      string = "name"
+	SortBySize      string = "size"
+	SortByModTime   string = "mtime"
+	SortByType      string = "type"
+	SortByExtension string = "extension"
+)
+
+// SortModes lists all sort modes in the order they are cycled through
+var SortModes = []string{SortByName, SortBySize, SortByModTime, SortByType, SortByExtension}
+
+// SortConfig describes how directory listings are ordered
+type SortConfig struct {
+	// PerDirectory stores changes for the current directory instead of the default
+	PerDirectory bool                    `yaml:"per_directory,omitempty"`
+	Default      SortSettings            `yaml:"default"`
+	Directories  map[string]SortSettings `yaml:"directories,omitempty"`
+}
+
+// SortSettings describes the order of a single listing
+type SortSettings struct {
+	By         string `yaml:"by"`
+	Descending bool   `yaml:"descending,omitempty"`
+	DirsFirst  bool   `yaml:"dirs_first"`
+}
+
+// DefaultSortConfig returns the order used when no configuration file exists
+func DefaultSortConfig() *SortConfig {
+	return &SortConfig{
+		Default: SortSettings{
+			By:        SortByName,
+			DirsFirst: true,
+		},
+	}
+}
+
+// LoadSortConfig reads the sort configuration from sortPath.
+// If no configuration file exists, the default order is returned.
+func LoadSortConfig(sortPath string) (*SortConfig, error) {
+	content, err := os.ReadFile(sortPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultSortConfig(), nil
+		}
+		return nil, fmt.Errorf("failed to read sort config: %v", err)
+	}
+
+	cfg := DefaultSortConfig()
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse sort config '%s': %v", sortPath, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sort config '%s': %v", sortPath, err)
+	}
+
+	return cfg, nil
+}
+
+// SaveSortConfig writes the sort configuration to sortPath
+func SaveSortConfig(sortPath string, cfg *SortConfig) error {
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode sort config: %v", err)
+	}
+
+	if err := os.WriteFile(sortPath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write sort config '%s': %v", sortPath, err)
+	}
+
+	return nil
+}
+
+// Validate checks that all sort modes are known
+func (c *SortConfig) Validate() error {
+	if err := c.Default.Validate(); err != nil {
+		return fmt.Errorf("default: %v", err)
+	}
+
+	for dir, settings := range c.Directories {
+		if err := settings.Validate(); err != nil {
+			return fmt.Errorf("directory '%s': %v", dir, err)
+		}
+	}
+
+	return nil
+}
+
+// For returns the settings for dir, falling back to the default
+func (c *SortConfig) For(dir string) SortSettings {
+	if settings, ok := c.Directories[path.Clean(dir)]; ok {
+		return settings
+	}
+	return c.Default
+}
+
+// Set stores settings for dir if PerDirectory is enabled, otherwise as default
+func (c *SortConfig) Set(dir string, settings SortSettings) {
+	if !c.PerDirectory {
+		c.Default = settings
+		return
+	}
+
+	if c.Directories == nil {
+		c.Directories = make(map[string]SortSettings)
+	}
+	c.Directories[path.Clean(dir)] = settings
+}
+
+// Validate checks that the sort mode is known
+func (s SortSettings) Validate() error {
+	for _, mode := range SortModes {
+		if s.By == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sort mode '%s'", s.By)
+}
+
+// Next returns the settings with the following sort mode
+func (s SortSettings) Next() SortSettings {
+	for i, mode := range SortModes {
+		if s.By == mode {
+			s.By = SortModes[(i+1)%len(SortModes)]
+			return s
+		}
+	}
+	s.By = SortByName
+	return s
+}
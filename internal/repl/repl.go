@@ -0,0 +1,316 @@
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/mwantia/vfsh/internal/shell"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	"github.com/peterh/liner"
+)
+
+// HistoryFileName is the name of the history file inside the config directory
+const HistoryFileName = "shell_history"
+
+// Shell is a line-based interactive shell for vfs commands
+type Shell struct {
+	adapter      *vfsadapter.Adapter
+	historyPath  string
+	bookmarkPath string
+	workingDir   string
+	out          io.Writer
+}
+
+// NewShell creates a new shell that stores its history in historyPath.
+// Bookmarks in bookmarkPath are shared with the TUI.
+func NewShell(adapter *vfsadapter.Adapter, historyPath, bookmarkPath string) *Shell {
+	return &Shell{
+		adapter:      adapter,
+		historyPath:  historyPath,
+		bookmarkPath: bookmarkPath,
+		workingDir:   "/",
+		out:          os.Stdout,
+	}
+}
+
+// Run reads and executes commands until the user exits the shell
+func (s *Shell) Run() error {
+	line := liner.NewLiner()
+	defer line.Close()
+
+	line.SetCtrlCAborts(true)
+	line.SetWordCompleter(s.complete)
+
+	s.loadHistory(line)
+	defer s.saveHistory(line)
+
+	for {
+		input, err := line.Prompt(s.prompt())
+		if err != nil {
+			if errors.Is(err, liner.ErrPromptAborted) {
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				fmt.Fprintln(s.out)
+				return nil
+			}
+			return fmt.Errorf("failed to read input: %v", err)
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+
+		args := shell.ParseCommandLine(input)
+		if len(args) == 0 {
+			continue
+		}
+
+		if exit := s.execute(args); exit {
+			return nil
+		}
+	}
+}
+
+// prompt returns the prompt showing the current working directory
+func (s *Shell) prompt() string {
+	return fmt.Sprintf("vfsh:%s> ", s.workingDir)
+}
+
+// execute handles shell builtins or forwards the command to the vfs.
+// It returns true if the shell should exit.
+func (s *Shell) execute(args []string) bool {
+	switch args[0] {
+	case "exit", "quit":
+		return true
+
+	case "pwd":
+		fmt.Fprintln(s.out, s.workingDir)
+		return false
+
+	case "cd":
+		target := "/"
+		if len(args) > 1 {
+			target = args[1]
+		}
+		if err := s.changeDirectory(target); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		return false
+
+	case "bookmark":
+		if err := s.setBookmark(args[1:]); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		return false
+
+	case "bookmarks":
+		if err := s.listBookmarks(); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		return false
+
+	case "jump":
+		if len(args) != 2 {
+			fmt.Fprintln(s.out, "Usage: jump <letter>")
+			return false
+		}
+		if err := s.jumpToBookmark(args[1]); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		return false
+	}
+
+	exitCode, err := s.adapter.Execute(s.out, s.resolveArgs(args)...)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+	} else if exitCode != 0 {
+		fmt.Fprintf(s.out, "Command exited with code %d\n", exitCode)
+	}
+
+	return false
+}
+
+// changeDirectory updates the working directory after validating the target
+func (s *Shell) changeDirectory(target string) error {
+	dir := s.resolvePath(target)
+	if dir == "/" {
+		s.workingDir = dir
+		return nil
+	}
+
+	entry, err := s.adapter.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("cd: %s: %v", target, err)
+	}
+	if !entry.IsDir {
+		return fmt.Errorf("cd: %s: not a directory", target)
+	}
+
+	s.workingDir = dir
+	return nil
+}
+
+// setBookmark stores the working directory, or the given directory, under a letter
+func (s *Shell) setBookmark(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: bookmark <letter> [directory]")
+	}
+
+	dir := s.workingDir
+	if len(args) == 2 {
+		dir = s.resolvePath(args[1])
+	}
+
+	if err := config.SetBookmark(s.bookmarkPath, args[0], dir); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Bookmark '%s' set to %s\n", args[0], dir)
+	return nil
+}
+
+// listBookmarks prints all bookmarks sorted by name
+func (s *Shell) listBookmarks() error {
+	bookmarks, err := config.LoadBookmarks(s.bookmarkPath)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(s.out, "%s  %s\n", name, bookmarks[name])
+	}
+	return nil
+}
+
+// jumpToBookmark changes into the directory stored under name
+func (s *Shell) jumpToBookmark(name string) error {
+	dir, err := config.LookupBookmark(s.bookmarkPath, name)
+	if err != nil {
+		return err
+	}
+	return s.changeDirectory(dir)
+}
+
+// resolvePath turns a path relative to the working directory into an absolute path
+func (s *Shell) resolvePath(p string) string {
+	if strings.HasPrefix(p, "/") {
+		return path.Clean(p)
+	}
+	return path.Join(s.workingDir, p)
+}
+
+// pathCommands are the file commands of the vfs, whose positional arguments are all paths.
+// Arguments of any other command are passed on unchanged.
+var pathCommands = map[string]bool{
+	"ls": true, "cat": true, "stat": true, "tree": true, "touch": true,
+	"mkdir": true, "rm": true, "rmdir": true, "cp": true, "mv": true,
+}
+
+// resolveArgs rewrites relative path arguments so they point into the working directory.
+// The vfs itself has no notion of a working directory. All positional arguments of
+// path commands are resolved, whether they exist yet or not.
+func (s *Shell) resolveArgs(args []string) []string {
+	if !pathCommands[args[0]] {
+		return args
+	}
+
+	resolved := make([]string, 0, len(args)+1)
+	resolved = append(resolved, args[0])
+
+	positional := 0
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "/") {
+			resolved = append(resolved, arg)
+			continue
+		}
+
+		positional++
+		resolved = append(resolved, s.resolvePath(arg))
+	}
+
+	// Listing without a path should show the working directory
+	if args[0] == "ls" && positional == 0 {
+		resolved = append(resolved, s.workingDir)
+	}
+
+	return resolved
+}
+
+// complete provides tab completion for vfs paths
+func (s *Shell) complete(line string, pos int) (string, []string, string) {
+	head := line[:pos]
+	tail := line[pos:]
+
+	// Only complete arguments, not the command itself
+	start := strings.LastIndex(head, " ") + 1
+	if start == 0 {
+		return head, nil, tail
+	}
+
+	word := head[start:]
+	head = head[:start]
+
+	dirPart := ""
+	prefix := word
+	if i := strings.LastIndex(word, "/"); i >= 0 {
+		dirPart = word[:i+1]
+		prefix = word[i+1:]
+	}
+
+	entries, err := s.adapter.ListDirectory(s.resolvePath(dirPart))
+	if err != nil {
+		return head, nil, tail
+	}
+
+	var completions []string
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name, prefix) {
+			continue
+		}
+		completion := dirPart + entry.Name
+		if entry.IsDir {
+			completion += "/"
+		}
+		completions = append(completions, completion)
+	}
+	sort.Strings(completions)
+
+	return head, completions, tail
+}
+
+// loadHistory reads previously stored history entries
+func (s *Shell) loadHistory(line *liner.State) {
+	file, err := os.Open(s.historyPath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	line.ReadHistory(file)
+}
+
+// saveHistory writes the history entries back to the config directory
+func (s *Shell) saveHistory(line *liner.State) {
+	file, err := os.OpenFile(s.historyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save history: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	line.WriteHistory(file)
+}
@@ -0,0 +1,37 @@
+package repl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveArgs(t *testing.T) {
+	s := &Shell{workingDir: "/data"}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"touch new.txt", "touch /data/new.txt"},
+		{"mkdir sub", "mkdir /data/sub"},
+		{"cp a.txt ../b.txt", "cp /data/a.txt /b.txt"},
+		{"rm -r old", "rm -r /data/old"},
+		{"cat /etc/hosts", "cat /etc/hosts"},
+		{"ls", "ls /data"},
+		{"ls -l", "ls -l /data"},
+		{"ls docs", "ls /data/docs"},
+		{"echo hello", "echo hello"},
+		{"echo ./hello", "echo ./hello"},
+		{"echo ../hello docs", "echo ../hello docs"},
+		{"mount list", "mount list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := strings.Join(s.resolveArgs(strings.Fields(tt.line)), " ")
+			if got != tt.want {
+				t.Fatalf("resolveArgs(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package shell
+
+import "strings"
+
+// ParseCommandLine splits a command line into tokens, honoring single and double quotes
+func ParseCommandLine(line string) []string {
+	var args []string
+	var current strings.Builder
+	inQuote := false
+	quoteChar := rune(0)
+
+	for _, ch := range line {
+		switch {
+		case ch == '"' || ch == '\'':
+			if inQuote {
+				if ch == quoteChar {
+					inQuote = false
+					quoteChar = 0
+				} else {
+					current.WriteRune(ch)
+				}
+			} else {
+				inQuote = true
+				quoteChar = ch
+			}
+
+		case ch == ' ' && !inQuote:
+			if current.Len() > 0 {
+				args = append(args, current.String())
+				current.Reset()
+			}
+
+		default:
+			current.WriteRune(ch)
+		}
+	}
+
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+
+	return args
+}
@@ -0,0 +1,33 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"ls", []string{"ls"}},
+		{"ls  -l   /demo ", []string{"ls", "-l", "/demo"}},
+		{`cat "my file.txt"`, []string{"cat", "my file.txt"}},
+		{`cat 'my file.txt'`, []string{"cat", "my file.txt"}},
+		{`echo "it's"`, []string{"echo", "it's"}},
+		{`echo 'say "hi"'`, []string{"echo", `say "hi"`}},
+		{`echo pre"fix suf"fix`, []string{"echo", "prefix suffix"}},
+		{`echo "unterminated arg`, []string{"echo", "unterminated arg"}},
+		{`echo ""`, []string{"echo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := ParseCommandLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/data"
+)
+
+// Get copies a vfs file or directory tree onto the host.
+// If hostPath is an existing directory, the source is placed inside it.
+func Get(ctx context.Context, fs vfs.VirtualFileSystem, vfsPath, hostPath string, opts Options) (*Summary, error) {
+	start := time.Now()
+	t := newTransfer(ctx, fs, opts)
+
+	source := path.Clean(vfsPath)
+	meta, err := fs.StatMetadata(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat '%s': %v", source, err)
+	}
+
+	target := hostPath
+	if info, err := os.Stat(hostPath); err == nil && info.IsDir() {
+		target = filepath.Join(hostPath, path.Base(source))
+	}
+
+	if meta.Mode.IsDir() {
+		if t.opts.OnProgress != nil {
+			if err := t.measureVirtual(source); err != nil {
+				return nil, err
+			}
+		}
+		err = t.getDirectory(source, target, meta.Mode, meta.ModifyTime)
+	} else {
+		t.state.TotalFiles, t.state.TotalBytes = 1, meta.Size
+		err = t.getFile(source, target, meta.Size, meta.Mode, meta.ModifyTime)
+	}
+
+	t.summary.Duration = time.Since(start)
+	return t.summary, err
+}
+
+// measureVirtual counts the files below a vfs directory for the progress totals
+func (t *transfer) measureVirtual(vfsPath string) error {
+	if err := t.checkCanceled(); err != nil {
+		return err
+	}
+
+	metas, err := t.fs.ReadDirectory(t.ctx, vfsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory '%s': %v", vfsPath, err)
+	}
+
+	for _, meta := range metas {
+		if meta.Mode.IsDir() {
+			if err := t.measureVirtual(path.Join(vfsPath, meta.Key)); err != nil {
+				return err
+			}
+			continue
+		}
+		t.state.TotalFiles++
+		t.state.TotalBytes += meta.Size
+	}
+	return nil
+}
+
+// getDirectory recursively recreates a vfs directory on the host.
+// Its mode and modification time are applied once the contents are written,
+// which would otherwise update the time or fail on a read-only mode.
+func (t *transfer) getDirectory(vfsPath, hostPath string, mode data.FileMode, modifyTime time.Time) error {
+	if err := t.checkCanceled(); err != nil {
+		return err
+	}
+
+	t.progress("mkdir %s", hostPath)
+	if !t.opts.DryRun {
+		if err := os.MkdirAll(hostPath, 0755); err != nil {
+			return fmt.Errorf("failed to create directory '%s': %v", hostPath, err)
+		}
+	}
+	t.summary.Directories++
+
+	metas, err := t.fs.ReadDirectory(t.ctx, vfsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read directory '%s': %v", vfsPath, err)
+	}
+
+	for _, meta := range metas {
+		source := path.Join(vfsPath, meta.Key)
+		target := filepath.Join(hostPath, meta.Key)
+
+		if meta.Mode.IsDir() {
+			err = t.getDirectory(source, target, meta.Mode, meta.ModifyTime)
+		} else {
+			err = t.getFile(source, target, meta.Size, meta.Mode, meta.ModifyTime)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	t.applyHostAttributes(hostPath, mode, modifyTime)
+	return nil
+}
+
+// getFile streams a single vfs file onto the host
+func (t *transfer) getFile(vfsPath, hostPath string, size int64, mode data.FileMode, modifyTime time.Time) error {
+	if err := t.checkCanceled(); err != nil {
+		return err
+	}
+
+	t.progress("get %s -> %s (%d bytes)", vfsPath, hostPath, size)
+
+	if t.opts.DryRun {
+		t.summary.Files++
+		t.summary.Bytes += size
+		return nil
+	}
+
+	src, err := t.fs.OpenFile(t.ctx, vfsPath, data.AccessModeRead)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", vfsPath, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(hostPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", hostPath, err)
+	}
+
+	written, err := t.copyFile(dst, src, vfsPath)
+	if closeErr := dst.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %v", hostPath, err)
+	}
+
+	t.applyHostAttributes(hostPath, mode, modifyTime)
+
+	t.summary.Files++
+	t.summary.Bytes += written
+	return nil
+}
@@ -0,0 +1,144 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/data"
+)
+
+// Put copies a host file or directory tree into the vfs.
+// If vfsPath is an existing directory, the source is placed inside it.
+// Modes and modification times are not kept, as the vfs has no API to set them.
+func Put(ctx context.Context, fs vfs.VirtualFileSystem, hostPath, vfsPath string, opts Options) (*Summary, error) {
+	start := time.Now()
+	t := newTransfer(ctx, fs, opts)
+
+	info, err := os.Stat(hostPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat '%s': %v", hostPath, err)
+	}
+
+	target := path.Clean(vfsPath)
+	if t.isVirtualDirectory(target) {
+		target = path.Join(target, filepath.Base(hostPath))
+	}
+
+	if t.opts.OnProgress != nil {
+		if err := t.measureHost(hostPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if info.IsDir() {
+		err = t.putDirectory(hostPath, target)
+	} else {
+		err = t.putFile(hostPath, target, info)
+	}
+
+	t.summary.Duration = time.Since(start)
+	return t.summary, err
+}
+
+// measureHost counts the regular files below hostRoot for the progress totals
+func (t *transfer) measureHost(hostRoot string) error {
+	return filepath.WalkDir(hostRoot, func(hostPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %v", hostPath, err)
+		}
+		if err := t.checkCanceled(); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat '%s': %v", hostPath, err)
+		}
+		t.state.TotalFiles++
+		t.state.TotalBytes += info.Size()
+		return nil
+	})
+}
+
+// putDirectory walks a host directory and recreates it in the vfs
+func (t *transfer) putDirectory(hostRoot, vfsRoot string) error {
+	return filepath.WalkDir(hostRoot, func(hostPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %v", hostPath, err)
+		}
+		if err := t.checkCanceled(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(hostRoot, hostPath)
+		if err != nil {
+			return err
+		}
+		target := path.Join(vfsRoot, filepath.ToSlash(rel))
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat '%s': %v", hostPath, err)
+		}
+
+		if entry.IsDir() {
+			if err := t.ensureVirtualDirectory(target); err != nil {
+				return fmt.Errorf("failed to create directory '%s': %v", target, err)
+			}
+			t.summary.Directories++
+			t.progress("mkdir %s", target)
+			return nil
+		}
+
+		// Symlinks, devices and other special files have no vfs equivalent
+		if !info.Mode().IsRegular() {
+			t.progress("skip %s (not a regular file)", hostPath)
+			return nil
+		}
+
+		return t.putFile(hostPath, target, info)
+	})
+}
+
+// putFile streams a single host file into the vfs
+func (t *transfer) putFile(hostPath, vfsPath string, info os.FileInfo) error {
+	t.progress("put %s -> %s (%d bytes)", hostPath, vfsPath, info.Size())
+
+	if t.opts.DryRun {
+		t.summary.Files++
+		t.summary.Bytes += info.Size()
+		return nil
+	}
+
+	src, err := os.Open(hostPath)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", hostPath, err)
+	}
+	defer src.Close()
+
+	dst, err := t.fs.OpenFile(t.ctx, vfsPath, data.AccessModeWrite|data.AccessModeCreate|data.AccessModeTrunc)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %v", vfsPath, err)
+	}
+
+	written, err := t.copyFile(dst, src, hostPath)
+	if closeErr := dst.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %v", vfsPath, err)
+	}
+
+	t.summary.Files++
+	t.summary.Bytes += written
+	return nil
+}
@@ -0,0 +1,183 @@
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/data"
+)
+
+// Options controls how a transfer is performed
+type Options struct {
+	DryRun     bool           // Only report what would be transferred
+	Progress   io.Writer      // Receives a line for every transferred entry (optional)
+	OnProgress func(Progress) // Receives byte progress while files are copied (optional)
+}
+
+// Progress describes how far a running transfer has come.
+// The totals are measured before the first file is copied.
+type Progress struct {
+	Path       string // File that is currently copied
+	Files      int    // Number of files completed
+	TotalFiles int    // Number of files to copy
+	Bytes      int64  // Number of bytes copied so far
+	TotalBytes int64  // Number of bytes to copy
+}
+
+// Summary contains the totals of a finished transfer
+type Summary struct {
+	Files       int
+	Directories int
+	Bytes       int64
+	Duration    time.Duration
+	Warnings    []string // Attributes that could not be preserved
+}
+
+// String returns a human-readable summary line
+func (s *Summary) String() string {
+	line := fmt.Sprintf("%d files, %d directories, %s in %s",
+		s.Files, s.Directories, humanize.IBytes(uint64(s.Bytes)), s.Duration.Round(time.Millisecond))
+	if len(s.Warnings) > 0 {
+		line += fmt.Sprintf(" (%d warnings)", len(s.Warnings))
+	}
+	return line
+}
+
+// transfer holds the state of a single put or get operation
+type transfer struct {
+	ctx     context.Context
+	fs      vfs.VirtualFileSystem
+	opts    Options
+	summary *Summary
+	state   Progress
+}
+
+func newTransfer(ctx context.Context, fs vfs.VirtualFileSystem, opts Options) *transfer {
+	return &transfer{
+		ctx:     ctx,
+		fs:      fs,
+		opts:    opts,
+		summary: &Summary{},
+	}
+}
+
+// progress writes a single progress line if a progress writer is configured
+func (t *transfer) progress(format string, args ...any) {
+	if t.opts.Progress == nil {
+		return
+	}
+
+	prefix := ""
+	if t.opts.DryRun {
+		prefix = "[dry-run] "
+	}
+	fmt.Fprintf(t.opts.Progress, prefix+format+"\n", args...)
+}
+
+// report sends the current progress if a progress callback is configured
+func (t *transfer) report() {
+	if t.opts.OnProgress != nil {
+		t.opts.OnProgress(t.state)
+	}
+}
+
+// copyFile streams src into dst and reports the progress of path along the way
+func (t *transfer) copyFile(dst io.Writer, src io.Reader, path string) (int64, error) {
+	t.state.Path = path
+	t.report()
+
+	written, err := io.Copy(&progressWriter{w: dst, t: t}, src)
+	if err == nil {
+		t.state.Files++
+		t.report()
+	}
+	return written, err
+}
+
+// progressWriter counts the bytes written through it towards the transfer progress
+type progressWriter struct {
+	w io.Writer
+	t *transfer
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.t.state.Bytes += int64(n)
+	p.t.report()
+	return n, err
+}
+
+// isVirtualDirectory reports whether path exists in the vfs and is a directory
+func (t *transfer) isVirtualDirectory(p string) bool {
+	if p == "/" {
+		return true
+	}
+
+	exists, _ := t.fs.LookupMetadata(t.ctx, p)
+	if !exists {
+		return false
+	}
+
+	meta, err := t.fs.StatMetadata(t.ctx, p)
+	if err != nil {
+		return false
+	}
+	return meta.Mode.IsDir()
+}
+
+// ensureVirtualDirectory creates a vfs directory unless it already exists
+func (t *transfer) ensureVirtualDirectory(p string) error {
+	if t.isVirtualDirectory(p) {
+		return nil
+	}
+	if t.opts.DryRun {
+		return nil
+	}
+	return t.fs.CreateDirectory(t.ctx, p)
+}
+
+// applyHostAttributes copies mode and modification time onto a host path.
+// Failures do not stop the transfer, but are reported as warnings in the summary.
+func (t *transfer) applyHostAttributes(p string, mode data.FileMode, modifyTime time.Time) {
+	if t.opts.DryRun {
+		return
+	}
+
+	if perm := hostPerm(mode); perm != 0 {
+		if err := os.Chmod(p, perm); err != nil {
+			t.warn("failed to set mode of '%s': %v", p, err)
+		}
+	}
+	if !modifyTime.IsZero() {
+		if err := os.Chtimes(p, modifyTime, modifyTime); err != nil {
+			t.warn("failed to set modification time of '%s': %v", p, err)
+		}
+	}
+}
+
+// hostPerm converts the permission bits of a vfs mode into a host mode.
+// Only the nine Unix permission bits are taken over, the bits above them are not
+// assumed to share the layout of os.FileMode.
+func hostPerm(mode data.FileMode) os.FileMode {
+	return os.FileMode(uint32(mode) & 0o777)
+}
+
+// warn records a problem that did not stop the transfer
+func (t *transfer) warn(format string, args ...any) {
+	t.summary.Warnings = append(t.summary.Warnings, fmt.Sprintf(format, args...))
+}
+
+// checkCanceled returns the context error once the transfer has been canceled
+func (t *transfer) checkCanceled() error {
+	select {
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// ClipboardOp represents what happens to clipboard entries on paste
+type ClipboardOp int
+
+const (
+	ClipboardCopy ClipboardOp = iota
+	ClipboardCut
+)
+
+// ConflictAction represents how name collisions are resolved on paste
+type ConflictAction int
+
+const (
+	ConflictOverwrite ConflictAction = iota
+	ConflictSkip
+	ConflictRename
+)
+
+// Clipboard holds the entries that were yanked or cut
+type Clipboard struct {
+	Op    ClipboardOp
+	Paths []string
+
+	jobID int // Job pasting the cut entries, the clipboard is cleared once it succeeds
+}
+
+// pasteRequest describes a copy or move of paths into a target directory
+type pasteRequest struct {
+	op            ClipboardOp
+	paths         []string
+	targetDir     string
+	fromClipboard bool // Clear the clipboard once a cut has been pasted
+}
+
+// setClipboard stores entries in the clipboard for a later paste
+func (m *Model) setClipboard(op ClipboardOp, entries []*vfsadapter.Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	m.clipboard = &Clipboard{Op: op, Paths: paths}
+
+	verb := "Yanked"
+	if op == ClipboardCut {
+		verb = "Cut"
+	}
+	if len(entries) == 1 {
+		m.statusMsg = fmt.Sprintf("%s: %s", verb, entries[0].Name)
+	} else {
+		m.statusMsg = fmt.Sprintf("%s: %d items", verb, len(entries))
+	}
+}
+
+// startPaste pastes the clipboard into the current directory
+func (m *Model) startPaste() tea.Cmd {
+	if m.clipboard == nil || len(m.clipboard.Paths) == 0 {
+		m.statusMsg = "Clipboard is empty"
+		return nil
+	}
+
+	return m.startPasteRequest(&pasteRequest{
+		op:            m.clipboard.Op,
+		paths:         m.clipboard.Paths,
+		targetDir:     m.pane().workingDirectory(),
+		fromClipboard: true,
+	})
+}
+
+// transferEntries copies or moves entries into targetDir without touching the clipboard.
+// Relative targets are resolved against the current directory.
+func (m *Model) transferEntries(op ClipboardOp, entries []*vfsadapter.Entry, targetDir string) tea.Cmd {
+	m.pendingEntries = nil
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if !filepath.IsAbs(targetDir) {
+		targetDir = filepath.Join(m.pane().workingDirectory(), targetDir)
+	}
+	targetDir = filepath.Clean(targetDir)
+
+	if !isDirectory(m.adapter, targetDir) {
+		m.errorMsg = fmt.Sprintf("Not a directory: %s", targetDir)
+		return nil
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	m.pane().clearSelection()
+
+	return m.startPasteRequest(&pasteRequest{op: op, paths: paths, targetDir: targetDir})
+}
+
+// startPasteRequest runs req, asking how to resolve name
+// collisions first if any of the targets already exist
+func (m *Model) startPasteRequest(req *pasteRequest) tea.Cmd {
+	conflicts := 0
+	for _, src := range req.paths {
+		target := filepath.Join(req.targetDir, filepath.Base(src))
+		if target != src && m.adapter.Exists(target) {
+			conflicts++
+		}
+	}
+
+	if conflicts > 0 {
+		m.pendingPaste = req
+		m.startInput(InputPasteConflict, fmt.Sprintf("%d item(s) already exist. (o)verwrite / (s)kip / (r)ename:", conflicts))
+		return nil
+	}
+
+	return m.paste(req, ConflictRename)
+}
+
+// submitPasteConflict parses the answer to the collision prompt
+func (m *Model) submitPasteConflict(value string) tea.Cmd {
+	req := m.pendingPaste
+	m.pendingPaste = nil
+	if req == nil {
+		return nil
+	}
+
+	switch strings.ToLower(value) {
+	case "o", "overwrite":
+		// Replacing a parent of an entry would delete the entry itself
+		for _, src := range req.paths {
+			target := filepath.Join(req.targetDir, filepath.Base(src))
+			if strings.HasPrefix(src, target+"/") {
+				m.errorMsg = fmt.Sprintf("Cannot overwrite '%s' with its own content", target)
+				return nil
+			}
+		}
+		return m.paste(req, ConflictOverwrite)
+	case "s", "skip":
+		return m.paste(req, ConflictSkip)
+	case "r", "rename":
+		return m.paste(req, ConflictRename)
+	}
+
+	m.statusMsg = "Paste canceled"
+	return nil
+}
+
+// paste starts a job that copies or moves all requested entries into the target directory
+func (m *Model) paste(req *pasteRequest, action ConflictAction) tea.Cmd {
+	description := fmt.Sprintf("Copy %d item(s)", len(req.paths))
+	if req.op == ClipboardCut {
+		description = fmt.Sprintf("Move %d item(s)", len(req.paths))
+	}
+
+	job := m.startJob(description, func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		pasted, skipped := 0, 0
+
+		for _, src := range req.paths {
+			target := filepath.Join(req.targetDir, filepath.Base(src))
+
+			if target == src {
+				// Cutting into the same directory is a no-op,
+				// copying into it always creates a renamed duplicate
+				if req.op == ClipboardCut {
+					skipped++
+					continue
+				}
+				target = uniqueName(adapter, target)
+			} else if adapter.Exists(target) {
+				switch action {
+				case ConflictSkip:
+					skipped++
+					continue
+				case ConflictRename:
+					target = uniqueName(adapter, target)
+				case ConflictOverwrite:
+					if err := overwrite(adapter, req.op, src, target, report); err != nil {
+						return "", err
+					}
+					pasted++
+					continue
+				}
+			}
+
+			var err error
+			if req.op == ClipboardCut {
+				err = adapter.Move(src, target, report)
+			} else {
+				err = adapter.Copy(src, target, report)
+			}
+			if err != nil {
+				return "", err
+			}
+
+			pasted++
+		}
+
+		return fmt.Sprintf("Pasted %d item(s), skipped %d", pasted, skipped), nil
+	})
+
+	if req.fromClipboard && req.op == ClipboardCut && m.clipboard != nil {
+		m.clipboard.jobID = job.ID
+	}
+
+	return nil
+}
+
+// overwrite copies or moves src onto the existing target. The entry is pasted under a
+// temporary name next to the target first, so the target is only replaced once that
+// succeeded and a failed or canceled paste leaves it untouched.
+func overwrite(adapter *vfsadapter.Adapter, op ClipboardOp, src, target string, report vfsadapter.ProgressFunc) error {
+	temp := uniqueName(adapter, filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".partial"))
+
+	// Once the copy is complete, replacing the target must not be interrupted by a cancel
+	cleanup := adapter.WithContext(context.WithoutCancel(adapter.Context()))
+
+	if op == ClipboardCut {
+		// Move restores the source on its own if it fails
+		if err := adapter.Move(src, temp, report); err != nil {
+			return err
+		}
+	} else if err := adapter.Copy(src, temp, report); err != nil {
+		_ = cleanup.Remove(temp, isDirectory(cleanup, temp))
+		return err
+	}
+
+	if err := cleanup.Remove(target, isDirectory(cleanup, target)); err != nil {
+		if op == ClipboardCut {
+			_ = cleanup.Move(temp, src, nil)
+		} else {
+			_ = cleanup.Remove(temp, isDirectory(cleanup, temp))
+		}
+		return fmt.Errorf("failed to overwrite '%s': %v", target, err)
+	}
+
+	if err := cleanup.Move(temp, target, nil); err != nil {
+		return fmt.Errorf("failed to overwrite '%s', pasted entry was left at '%s': %v", target, temp, err)
+	}
+	return nil
+}
+
+// uniqueName appends a numeric suffix to path until it no longer exists
+func uniqueName(adapter *vfsadapter.Adapter, path string) string {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(filepath.Base(path), ext)
+
+	for i := 1; ; i++ {
+		candidate := filepath.Join(dir, fmt.Sprintf("%s_%d%s", base, i, ext))
+		if !adapter.Exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// isDirectory reports whether path is an existing directory
+func isDirectory(adapter *vfsadapter.Adapter, path string) bool {
+	entry, err := adapter.Stat(path)
+	return err == nil && entry.IsDir
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/mount/backend/ephemeral"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// newTestAdapter creates an adapter on an ephemeral root mount.
+// Paths in files that end with a slash are created as directories.
+func newTestAdapter(t *testing.T, files map[string]string) *vfsadapter.Adapter {
+	t.Helper()
+
+	ctx := context.Background()
+	fs, err := vfs.NewVirtualFileSystem(vfs.WithLogFile(filepath.Join(t.TempDir(), "vfs.log")), vfs.WithoutTerminalLog())
+	if err != nil {
+		t.Fatalf("failed to setup vfs: %v", err)
+	}
+	t.Cleanup(func() { _ = fs.Shutdown(ctx) })
+
+	if err := fs.Mount(ctx, "/", ephemeral.NewEphemeralBackend()); err != nil {
+		t.Fatalf("failed to mount '/': %v", err)
+	}
+
+	// Sorting creates every directory before its children
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	adapter := vfsadapter.New(ctx, fs)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "/") {
+			err = adapter.CreateDirectory(strings.TrimSuffix(path, "/"))
+		} else {
+			err = adapter.WriteFile(path, []byte(files[path]))
+		}
+		if err != nil {
+			t.Fatalf("failed to create '%s': %v", path, err)
+		}
+	}
+
+	return adapter
+}
+
+// listTestTree returns every file below dir with its content, directories map to ""
+func listTestTree(t *testing.T, adapter *vfsadapter.Adapter, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := adapter.ListDirectory(dir)
+	if err != nil {
+		t.Fatalf("failed to list '%s': %v", dir, err)
+	}
+
+	tree := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir {
+			tree[entry.Path+"/"] = ""
+			for path, content := range listTestTree(t, adapter, entry.Path) {
+				tree[path] = content
+			}
+			continue
+		}
+
+		reader, err := adapter.StreamFile(entry.Path)
+		if err != nil {
+			t.Fatalf("failed to open '%s': %v", entry.Path, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("failed to read '%s': %v", entry.Path, err)
+		}
+		tree[entry.Path] = string(content)
+	}
+
+	return tree
+}
+
+// equalTrees reports whether both trees hold the same paths with the same content
+func equalTrees(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, content := range a {
+		if other, ok := b[path]; !ok || other != content {
+			return false
+		}
+	}
+	return true
+}
+
+func TestUniqueName(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		path  string
+		want  string
+	}{
+		{"free name", nil, "/data/a.txt", "/data/a_1.txt"},
+		{"one collision", map[string]string{"/data/a_1.txt": ""}, "/data/a.txt", "/data/a_2.txt"},
+		{"several collisions", map[string]string{"/data/a_1.txt": "", "/data/a_2.txt": "", "/data/a_3.txt": ""}, "/data/a.txt", "/data/a_4.txt"},
+		{"gap is reused", map[string]string{"/data/a_2.txt": ""}, "/data/a.txt", "/data/a_1.txt"},
+		{"directory", map[string]string{"/data/dir/": ""}, "/data/dir", "/data/dir_1"},
+		{"archive keeps last extension", nil, "/data/backup.tar.gz", "/data/backup.tar_1.gz"},
+		{"partial paste", nil, "/data/.a.txt.partial", "/data/.a.txt_1.partial"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"/data/": ""}
+			for path, content := range tt.files {
+				files[path] = content
+			}
+			adapter := newTestAdapter(t, files)
+
+			if got := uniqueName(adapter, tt.path); got != tt.want {
+				t.Fatalf("uniqueName(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverwrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		op     ClipboardOp
+		files  map[string]string
+		src    string
+		target string
+		want   map[string]string // Full tree after the paste
+	}{
+		{
+			name:   "copy file",
+			op:     ClipboardCopy,
+			files:  map[string]string{"/src/": "", "/src/a.txt": "new", "/dst/": "", "/dst/a.txt": "old"},
+			src:    "/src/a.txt",
+			target: "/dst/a.txt",
+			want:   map[string]string{"/src/": "", "/src/a.txt": "new", "/dst/": "", "/dst/a.txt": "new"},
+		},
+		{
+			name:   "cut file",
+			op:     ClipboardCut,
+			files:  map[string]string{"/src/": "", "/src/a.txt": "new", "/dst/": "", "/dst/a.txt": "old"},
+			src:    "/src/a.txt",
+			target: "/dst/a.txt",
+			want:   map[string]string{"/src/": "", "/dst/": "", "/dst/a.txt": "new"},
+		},
+		{
+			name: "copy directory replaces its content",
+			op:   ClipboardCopy,
+			files: map[string]string{
+				"/src/": "", "/src/dir/": "", "/src/dir/a.txt": "new",
+				"/dst/": "", "/dst/dir/": "", "/dst/dir/a.txt": "old", "/dst/dir/stale.txt": "old",
+			},
+			src:    "/src/dir",
+			target: "/dst/dir",
+			want: map[string]string{
+				"/src/": "", "/src/dir/": "", "/src/dir/a.txt": "new",
+				"/dst/": "", "/dst/dir/": "", "/dst/dir/a.txt": "new",
+			},
+		},
+		{
+			name: "cut file onto directory",
+			op:   ClipboardCut,
+			files: map[string]string{
+				"/src/": "", "/src/a": "new",
+				"/dst/": "", "/dst/a/": "", "/dst/a/old.txt": "old",
+			},
+			src:    "/src/a",
+			target: "/dst/a",
+			want:   map[string]string{"/src/": "", "/dst/": "", "/dst/a": "new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newTestAdapter(t, tt.files)
+
+			if err := overwrite(adapter, tt.op, tt.src, tt.target, nil); err != nil {
+				t.Fatalf("overwrite(%q, %q) = %v, want nil", tt.src, tt.target, err)
+			}
+
+			if got := listTestTree(t, adapter, "/"); !equalTrees(got, tt.want) {
+				t.Fatalf("tree after overwrite = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverwriteCanceledKeepsTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		op   ClipboardOp
+	}{
+		{"copy", ClipboardCopy},
+		{"cut", ClipboardCut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{
+				"/src/": "", "/src/a.txt": strings.Repeat("x", 1<<20),
+				"/dst/": "", "/dst/a.txt": "old",
+			}
+			adapter := newTestAdapter(t, files)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			err := overwrite(adapter.WithContext(ctx), tt.op, "/src/a.txt", "/dst/a.txt", func(vfsadapter.CopyProgress) { cancel() })
+			if err == nil {
+				t.Fatalf("overwrite() = nil, want an error after cancel")
+			}
+
+			if got := listTestTree(t, adapter, "/"); !equalTrees(got, files) {
+				t.Fatalf("tree after canceled overwrite differs from the tree before")
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// minNameWidth is the narrowest the name may get before columns are dropped
+const minNameWidth = 12
+
+// columnWidth returns how many cells a column takes up
+func columnWidth(column, timeFormat string) int {
+	switch column {
+	case config.ColumnSize:
+		return 10
+	case config.ColumnModTime:
+		if timeFormat == config.TimeFormatAbsolute {
+			return 16
+		}
+		return 8
+	case config.ColumnMode:
+		return 10
+	case config.ColumnType:
+		return 20
+	default:
+		return 0
+	}
+}
+
+// fitColumns drops columns from the end until the name keeps at least minNameWidth
+// cells of width, and returns the remaining columns together with the name width
+func fitColumns(columns []string, timeFormat string, width int) ([]string, int) {
+	for n := len(columns); n >= 0; n-- {
+		nameWidth := width
+		for _, column := range columns[:n] {
+			nameWidth -= columnWidth(column, timeFormat) + 1
+		}
+		if nameWidth >= minNameWidth || n == 0 {
+			return columns[:n], max(nameWidth, 0)
+		}
+	}
+	return nil, width
+}
+
+// columnValue returns the text of a single column for entry
+func columnValue(entry *vfsadapter.Entry, column, timeFormat string, now time.Time) string {
+	switch column {
+	case config.ColumnSize:
+		return entry.DisplaySize()
+	case config.ColumnModTime:
+		if timeFormat == config.TimeFormatAbsolute {
+			return entry.ModTime.Format("2006-01-02 15:04")
+		}
+		return relativeTime(entry.ModTime, now)
+	case config.ColumnMode:
+		return entry.DisplayMode()
+	case config.ColumnType:
+		if entry.IsDir || entry.FileType().MimeType == "" {
+			return "-"
+		}
+		return entry.FileType().MimeType
+	default:
+		return ""
+	}
+}
+
+// relativeTime formats t as short age relative to now, such as "5m ago"
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	age := now.Sub(t)
+	switch {
+	case age < 0:
+		return t.Format("2006-01-02")
+	case age < time.Minute:
+		return "now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(age/(30*24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dy ago", int(age/(365*24*time.Hour)))
+	}
+}
+
+// padRight truncates or pads s with spaces to exactly width cells
+func padRight(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", max(width-runewidth.StringWidth(s), 0))
+}
+
+// padLeft truncates or right-aligns s to exactly width cells
+func padLeft(s string, width int) string {
+	s = truncate(s, width)
+	return strings.Repeat(" ", max(width-runewidth.StringWidth(s), 0)) + s
+}
+
+// formatLongEntry formats the name and the configured columns to fit into width
+func (m *Model) formatLongEntry(entry *vfsadapter.Entry, width int, now time.Time) string {
+	columns, nameWidth := fitColumns(m.listing.Columns, m.listing.TimeFormat, width)
+
+	var b strings.Builder
+	b.WriteString(padRight(entry.DisplayName(), nameWidth))
+
+	for _, column := range columns {
+		value := columnValue(entry, column, m.listing.TimeFormat, now)
+		b.WriteString(" ")
+		if column == config.ColumnSize {
+			b.WriteString(padLeft(value, columnWidth(column, m.listing.TimeFormat)))
+		} else {
+			b.WriteString(padRight(value, columnWidth(column, m.listing.TimeFormat)))
+		}
+	}
+
+	return b.String()
+}
+
+// toggleLongListing switches the current tab between the short and the long listing
+func (m *Model) toggleLongListing() {
+	m.tab().longListing = !m.tab().longListing
+	if m.tab().longListing {
+		m.statusMsg = "Long listing"
+	} else {
+		m.statusMsg = "Short listing"
+	}
+}
@@ -0,0 +1,430 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tomlNumber matches integers, floats and their special values
+	tomlNumber = regexp.MustCompile(`^([+-]?(0|[1-9](_?[0-9])*)(\.[0-9](_?[0-9])*)?([eE][+-]?[0-9](_?[0-9])*)?|0x[0-9A-Fa-f](_?[0-9A-Fa-f])*|0o[0-7](_?[0-7])*|0b[01](_?[01])*|[+-]?(inf|nan))$`)
+	// tomlDateTime matches offset and local date-times, dates and times
+	tomlDateTime = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?([Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}(:\d{2}(\.\d+)?)?)$`)
+)
+
+// tomlParser validates a TOML document. It checks the syntax and duplicate keys and tables,
+// but does not build the document, since the preview shows the source.
+type tomlParser struct {
+	text    string
+	pos     int
+	table   string          // Path of the current table
+	defined map[string]bool // Keys and tables defined so far
+	arrays  map[string]bool // Tables defined as array of tables
+}
+
+// validateTOML returns the first error in a TOML document, or nil if it is valid
+func validateTOML(text string) *parseError {
+	p := &tomlParser{
+		text:    text,
+		defined: make(map[string]bool),
+		arrays:  make(map[string]bool),
+	}
+
+	for {
+		p.skipBlank(true)
+		if p.pos >= len(p.text) {
+			return nil
+		}
+
+		var perr *parseError
+		if p.peek() == '[' {
+			perr = p.tableHeader()
+		} else {
+			perr = p.keyValue(p.table, p.defined)
+		}
+		if perr == nil {
+			perr = p.endOfLine()
+		}
+		if perr != nil {
+			return perr
+		}
+	}
+}
+
+// fail returns an error at the current position
+func (p *tomlParser) fail(format string, args ...any) *parseError {
+	return errorAt(p.text, p.pos, fmt.Sprintf(format, args...))
+}
+
+// peek returns the current byte, or 0 at the end
+func (p *tomlParser) peek() byte {
+	if p.pos >= len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+// skipBlank skips spaces and tabs, and with newlines also line breaks and comments
+func (p *tomlParser) skipBlank(newlines bool) {
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case newlines && (c == '\n' || c == '\r'):
+			p.pos++
+		case newlines && c == '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// skipComment skips to the end of the line
+func (p *tomlParser) skipComment() {
+	if end := strings.IndexByte(p.text[p.pos:], '\n'); end >= 0 {
+		p.pos += end
+	} else {
+		p.pos = len(p.text)
+	}
+}
+
+// endOfLine requires the rest of the line to be blank or a comment
+func (p *tomlParser) endOfLine() *parseError {
+	p.skipBlank(false)
+	switch p.peek() {
+	case 0, '\n', '\r':
+		return nil
+	case '#':
+		p.skipComment()
+		return nil
+	default:
+		return p.fail("expected end of line, found %q", p.peek())
+	}
+}
+
+// tableHeader parses [table] or [[array.of.tables]]
+func (p *tomlParser) tableHeader() *parseError {
+	array := strings.HasPrefix(p.text[p.pos:], "[[")
+	if array {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+
+	p.skipBlank(false)
+	start := p.pos
+	path, perr := p.key()
+	if perr != nil {
+		return perr
+	}
+	p.skipBlank(false)
+
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.text[p.pos:], closing) {
+		return p.fail("expected %s to close table header", closing)
+	}
+	p.pos += len(closing)
+
+	switch {
+	case array:
+		if p.defined[path] && !p.arrays[path] {
+			return errorAt(p.text, start, fmt.Sprintf("table %s is already defined", path))
+		}
+		p.arrays[path] = true
+		// Every element of an array of tables starts with fresh keys
+		for key := range p.defined {
+			if strings.HasPrefix(key, path+".") {
+				delete(p.defined, key)
+			}
+		}
+	case p.defined[path]:
+		return errorAt(p.text, start, fmt.Sprintf("table %s is already defined", path))
+	}
+
+	p.defined[path] = true
+	p.table = path
+	return nil
+}
+
+// key parses a bare, quoted or dotted key and returns its normalized path
+func (p *tomlParser) key() (string, *parseError) {
+	var parts []string
+	for {
+		p.skipBlank(false)
+		switch c := p.peek(); {
+		case c == '"' || c == '\'':
+			start := p.pos
+			if perr := p.stringValue(); perr != nil {
+				return "", perr
+			}
+			parts = append(parts, p.text[start+1:p.pos-1])
+		case isBareKeyChar(c):
+			start := p.pos
+			for isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			parts = append(parts, p.text[start:p.pos])
+		default:
+			return "", p.fail("expected key")
+		}
+
+		p.skipBlank(false)
+		if p.peek() != '.' {
+			return strings.Join(parts, "."), nil
+		}
+		p.pos++
+	}
+}
+
+// isBareKeyChar reports whether c may appear in an unquoted key
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// keyValue parses key = value within table, recording the key in defined
+func (p *tomlParser) keyValue(table string, defined map[string]bool) *parseError {
+	start := p.pos
+	key, perr := p.key()
+	if perr != nil {
+		return perr
+	}
+
+	path := key
+	if table != "" {
+		path = table + "." + key
+	}
+	if defined[path] {
+		return errorAt(p.text, start, fmt.Sprintf("key %s is already defined", key))
+	}
+	defined[path] = true
+
+	p.skipBlank(false)
+	if p.peek() != '=' {
+		return p.fail("expected = after key %s", key)
+	}
+	p.pos++
+	p.skipBlank(false)
+
+	return p.value()
+}
+
+// value parses any value
+func (p *tomlParser) value() *parseError {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.stringValue()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inlineTable()
+	case c == 0 || c == '\n' || c == '\r' || c == '#':
+		return p.fail("expected value")
+	}
+
+	// Numbers, booleans and dates run up to the next delimiter. The space between
+	// the date and the time of a date-time belongs to the value.
+	start := p.pos
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if c == ' ' && tomlDateTime.MatchString(p.text[start:p.pos]) && p.pos+1 < len(p.text) && isDigit(p.text[p.pos+1]) {
+			p.pos++
+			continue
+		}
+		if strings.IndexByte(" \t\r\n,]}#", c) >= 0 {
+			break
+		}
+		p.pos++
+	}
+
+	word := p.text[start:p.pos]
+	if word == "true" || word == "false" || tomlNumber.MatchString(word) || tomlDateTime.MatchString(word) {
+		return nil
+	}
+	p.pos = start
+	return p.fail("invalid value %q", word)
+}
+
+// stringValue parses a basic, literal or multi-line string
+func (p *tomlParser) stringValue() *parseError {
+	quote := p.text[p.pos : p.pos+1]
+	multiline := strings.HasPrefix(p.text[p.pos:], strings.Repeat(quote, 3))
+	if multiline {
+		quote = strings.Repeat(quote, 3)
+	}
+	start := p.pos
+	p.pos += len(quote)
+
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		switch {
+		case strings.HasPrefix(p.text[p.pos:], quote):
+			p.pos += len(quote)
+			// Up to two more quotes may directly precede the closing delimiter
+			for extra := 0; multiline && extra < 2 && strings.HasPrefix(p.text[p.pos:], quote[:1]); extra++ {
+				p.pos++
+			}
+			return nil
+		case c == '\n' && !multiline:
+			return p.fail("unterminated string")
+		case c == '\\' && quote[0] == '"':
+			if perr := p.escape(multiline); perr != nil {
+				return perr
+			}
+			continue
+		}
+		p.pos++
+	}
+
+	p.pos = start
+	return p.fail("unterminated string")
+}
+
+// escape parses an escape sequence in a basic string
+func (p *tomlParser) escape(multiline bool) *parseError {
+	if p.pos+1 >= len(p.text) {
+		return p.fail("unterminated string")
+	}
+
+	switch c := p.text[p.pos+1]; c {
+	case 'b', 't', 'n', 'f', 'r', '"', '\\':
+		p.pos += 2
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		hex := p.text[p.pos+2 : min(p.pos+2+n, len(p.text))]
+		if len(hex) < n || strings.Trim(hex, "0123456789abcdefABCDEF") != "" {
+			return p.fail("invalid unicode escape")
+		}
+		p.pos += 2 + n
+	case ' ', '\t', '\r', '\n':
+		// A backslash at the end of a line continues a multi-line string
+		if !multiline {
+			return p.fail("invalid escape sequence \\%c", c)
+		}
+		p.pos++
+		p.skipBlank(true)
+	default:
+		return p.fail("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+// array parses [value, ...], which may span lines
+func (p *tomlParser) array() *parseError {
+	p.pos++
+	for {
+		p.skipBlank(true)
+		if p.peek() == ']' {
+			p.pos++
+			return nil
+		}
+		if p.pos >= len(p.text) {
+			return p.fail("unterminated array")
+		}
+
+		if perr := p.value(); perr != nil {
+			return perr
+		}
+
+		p.skipBlank(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return nil
+		default:
+			return p.fail("expected , or ] in array")
+		}
+	}
+}
+
+// inlineTable parses {key = value, ...} on a single line
+func (p *tomlParser) inlineTable() *parseError {
+	p.pos++
+	defined := make(map[string]bool)
+
+	p.skipBlank(false)
+	if p.peek() == '}' {
+		p.pos++
+		return nil
+	}
+
+	for {
+		p.skipBlank(false)
+		if perr := p.keyValue("", defined); perr != nil {
+			return perr
+		}
+
+		p.skipBlank(false)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return nil
+		default:
+			return p.fail("expected , or } in inline table")
+		}
+	}
+}
+
+// validateINI returns the first malformed line of an INI file, or nil if it is valid.
+// Lines are sections, key = value or key: value pairs, comments, or indented continuations.
+func validateINI(text string) *parseError {
+	sections := make(map[string]bool)
+	hasKey := false
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		fail := func(column int, msg string) *parseError {
+			return &parseError{line: i + 1, column: column, msg: msg}
+		}
+
+		switch {
+		case trimmed == "", trimmed[0] == '#', trimmed[0] == ';':
+			continue
+
+		case trimmed[0] == '[':
+			end := strings.IndexByte(trimmed, ']')
+			if end < 0 {
+				return fail(indent+len(trimmed)+1, "expected ] to close section")
+			}
+			if rest := strings.TrimSpace(trimmed[end+1:]); rest != "" && rest[0] != '#' && rest[0] != ';' {
+				return fail(indent+end+2, "unexpected text after section")
+			}
+			name := strings.TrimSpace(trimmed[1:end])
+			if name == "" {
+				return fail(indent+1, "empty section name")
+			}
+			if sections[name] {
+				return fail(indent+1, fmt.Sprintf("section %s is already defined", name))
+			}
+			sections[name] = true
+			hasKey = false
+
+		case indent > 0 && hasKey:
+			// Continuation of the previous value
+
+		default:
+			sep := strings.IndexAny(trimmed, "=:")
+			if sep < 0 {
+				return fail(indent+1, "expected key = value")
+			}
+			if strings.TrimSpace(trimmed[:sep]) == "" {
+				return fail(indent+1, "missing key")
+			}
+			hasKey = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package tui
+
+import "testing"
+
+func TestValidateTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line int // Line of the expected error, 0 if valid
+	}{
+		{"empty", "", 0},
+		{"key values", "name = \"vfsh\"\nport = 8080\nenabled = true\n", 0},
+		{"tables", "[server]\nhost = \"localhost\"\n\n[server.tls]\ncert = 'a.pem'\n", 0},
+		{"array of tables", "[[mount]]\npath = \"/\"\n[[mount]]\npath = \"/data\"\n", 0},
+		{"inline table and array", "point = { x = 1, y = 2 }\nlist = [1, 2, 3]\n", 0},
+		{"comments", "# comment\nkey = 1 # trailing\n", 0},
+		{"missing value", "key =\n", 1},
+		{"missing equals", "ok = 1\nkey\n", 2},
+		{"duplicate key", "key = 1\nkey = 2\n", 2},
+		{"duplicate table", "[a]\nx = 1\n[a]\ny = 2\n", 3},
+		{"unclosed string", "key = \"value\n", 1},
+		{"unclosed table", "[table\nkey = 1\n", 1},
+		{"trailing garbage", "key = 1 2\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perr := validateTOML(tt.text)
+			switch {
+			case tt.line == 0 && perr != nil:
+				t.Fatalf("unexpected error: %v", perr)
+			case tt.line != 0 && perr == nil:
+				t.Fatalf("expected an error on line %d", tt.line)
+			case tt.line != 0 && perr.line != tt.line:
+				t.Fatalf("error %q on line %d, want line %d", perr, perr.line, tt.line)
+			}
+		})
+	}
+}
+
+func TestValidateINI(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		line int // Line of the expected error, 0 if valid
+	}{
+		{"empty", "", 0},
+		{"sections", "[main]\nkey = value\n\n[other]\nname: test\n", 0},
+		{"comments", "; comment\n# comment\n[main]\nkey=value\n", 0},
+		{"keys before sections", "key = value\n[main]\nother = 1\n", 0},
+		{"continuation", "[main]\nkey = first\n  second\n", 0},
+		{"windows line endings", "[main]\r\nkey = value\r\n", 0},
+		{"unclosed section", "[main\nkey = value\n", 1},
+		{"missing separator", "[main]\nkey value\n", 2},
+		{"empty key", "[main]\n= value\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perr := validateINI(tt.text)
+			switch {
+			case tt.line == 0 && perr != nil:
+				t.Fatalf("unexpected error: %v", perr)
+			case tt.line != 0 && perr == nil:
+				t.Fatalf("expected an error on line %d", tt.line)
+			case tt.line != 0 && perr.line != tt.line:
+				t.Fatalf("error %q on line %d, want line %d", perr, perr.line, tt.line)
+			}
+		})
+	}
+}
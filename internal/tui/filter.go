@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// regexPrefix marks a filter pattern as regular expression
+const regexPrefix = "re:"
+
+// compileFilter returns a matcher for entry names. Patterns starting with "re:"
+// are regular expressions, patterns containing glob characters are globs, and
+// everything else is a case-insensitive substring.
+func compileFilter(pattern string) (func(name string) bool, error) {
+	if expr, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		return re.MatchString, nil
+	}
+
+	if strings.ContainsAny(pattern, "*?[") {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob: %v", err)
+		}
+		return func(name string) bool {
+			matched, _ := filepath.Match(pattern, name)
+			return matched
+		}, nil
+	}
+
+	lower := strings.ToLower(pattern)
+	return func(name string) bool {
+		return strings.Contains(strings.ToLower(name), lower)
+	}, nil
+}
+
+// applyFilter narrows the listing to entries matching the filter.
+// An invalid pattern leaves the current listing untouched.
+func (p *Pane) applyFilter() error {
+	if p.filter == "" {
+		p.entries = p.allEntries
+		return nil
+	}
+
+	match, err := compileFilter(p.filter)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]*vfsadapter.Entry, 0, len(p.allEntries))
+	for _, entry := range p.allEntries {
+		if match(entry.Name) {
+			filtered = append(filtered, entry)
+		}
+	}
+	p.entries = filtered
+	return nil
+}
+
+// setFilter changes the filter and keeps the cursor on the same entry if it still matches
+func (p *Pane) setFilter(pattern string, visibleLines int) error {
+	current := p.currentEntry()
+	p.commitVisual()
+
+	p.filter = pattern
+	err := p.applyFilter()
+	p.focusEntry(current, visibleLines)
+	return err
+}
+
+// focusEntry moves the cursor onto entry, or to the top if it is not listed
+func (p *Pane) focusEntry(entry *vfsadapter.Entry, visibleLines int) {
+	p.cursor = 0
+	if len(p.entries) == 0 {
+		p.offset = 0
+		return
+	}
+
+	if entry != nil {
+		for i, e := range p.entries {
+			if e.Path == entry.Path {
+				p.cursor = i
+				break
+			}
+		}
+	}
+	p.moveCursor(0, visibleLines)
+}
+
+// cycleMatch moves the cursor to the next or previous match, wrapping around at both ends
+func (p *Pane) cycleMatch(delta, visibleLines int) {
+	if len(p.entries) == 0 {
+		return
+	}
+	p.cursor = (p.cursor + delta + len(p.entries)) % len(p.entries)
+	p.moveCursor(0, visibleLines)
+}
+
+// startFilter enters filter mode with the current filter as initial value
+func (m *Model) startFilter() {
+	m.mode = ModeFilter
+	m.textInput.Placeholder = "Filter (substring, glob or re:regex)"
+	m.textInput.SetValue(m.pane().filter)
+	m.textInput.Focus()
+	m.errorMsg = ""
+	m.statusMsg = ""
+}
+
+// clearFilter restores the full listing and keeps the cursor on the same entry
+func (m *Model) clearFilter() {
+	m.pane().setFilter("", m.getPaneLines())
+	m.errorMsg = ""
+}
+
+// handleFilterMode processes keys while typing a filter
+func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.mode = ModeNormal
+		m.textInput.Blur()
+		m.clearFilter()
+		return m, m.updatePreview()
+
+	case tea.KeyEnter:
+		// Keep the filter and return to browsing the narrowed list
+		m.mode = ModeNormal
+		m.textInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+
+	m.errorMsg = ""
+	if err := m.pane().setFilter(m.textInput.Value(), m.getPaneLines()); err != nil {
+		m.errorMsg = err.Error()
+	}
+
+	return m, tea.Batch(cmd, m.updatePreview())
+}
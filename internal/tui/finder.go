@@ -0,0 +1,394 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// IndexLimit bounds how much of a single mount the fuzzy finder indexes
+type IndexLimit struct {
+	MaxDepth   int // Directory levels below the mount point that are walked
+	MaxEntries int // Entries indexed for the mount
+}
+
+// DefaultIndexLimit applies to mounts without a configured limit
+var DefaultIndexLimit = IndexLimit{MaxDepth: 8, MaxEntries: 10000}
+
+// maxFinderResults limits how many matches are kept after filtering
+const maxFinderResults = 200
+
+// Messages sent from the index walk
+type finderBatchMsg struct {
+	generation int
+	entries    []*vfsadapter.Entry
+}
+
+type finderDoneMsg struct {
+	generation int
+	err        error
+}
+
+// Finder indexes the vfs tree in the background and filters it by a fuzzy query
+type Finder struct {
+	adapter *vfsadapter.Adapter
+	limits  map[string]IndexLimit // Limits keyed by mount path
+
+	index    []*vfsadapter.Entry
+	matches  []finderMatch // Best matches of the index, ranked
+	results  []*vfsadapter.Entry
+	query    string
+	cursor   int
+	indexing bool
+	err      error
+
+	generation int // Generation counter to drop messages from canceled walks
+	cancel     context.CancelFunc
+	updates    chan tea.Msg
+	walking    sync.WaitGroup
+}
+
+// NewFinder creates a finder that walks the vfs through adapter
+func NewFinder(adapter *vfsadapter.Adapter) *Finder {
+	return &Finder{
+		adapter: adapter,
+		limits:  make(map[string]IndexLimit),
+	}
+}
+
+// SetLimit overrides the index limit for the mount at path
+func (f *Finder) SetLimit(path string, limit IndexLimit) {
+	f.limits[filepath.Clean(path)] = limit
+}
+
+// limit returns the index limit for the mount at path
+func (f *Finder) limit(path string) IndexLimit {
+	limit, ok := f.limits[path]
+	if !ok {
+		return DefaultIndexLimit
+	}
+	if limit.MaxDepth <= 0 {
+		limit.MaxDepth = DefaultIndexLimit.MaxDepth
+	}
+	if limit.MaxEntries <= 0 {
+		limit.MaxEntries = DefaultIndexLimit.MaxEntries
+	}
+	return limit
+}
+
+// Start cancels any running walk and indexes the tree from scratch
+func (f *Finder) Start() tea.Cmd {
+	f.Stop()
+
+	ctx, cancel := context.WithCancel(f.adapter.Context())
+	f.generation++
+	f.cancel = cancel
+	f.index = nil
+	f.matches = nil
+	f.results = nil
+	f.cursor = 0
+	f.indexing = true
+	f.err = nil
+	f.updates = make(chan tea.Msg, 16)
+
+	generation := f.generation
+	updates := f.updates
+	adapter := f.adapter.WithContext(ctx)
+
+	f.walking.Add(1)
+	go func() {
+		defer f.walking.Done()
+		defer close(updates)
+		defer cancel()
+
+		err := f.walk(ctx, adapter, func(entries []*vfsadapter.Entry) bool {
+			select {
+			case updates <- finderBatchMsg{generation: generation, entries: entries}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+
+		select {
+		case updates <- finderDoneMsg{generation: generation, err: err}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return f.Listen()
+}
+
+// Listen waits for the next message from the running walk
+func (f *Finder) Listen() tea.Cmd {
+	updates := f.updates
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// Stop cancels the running walk, keeping everything indexed so far
+func (f *Finder) Stop() {
+	if f.cancel != nil {
+		f.cancel()
+		f.cancel = nil
+	}
+	f.indexing = false
+}
+
+// Wait blocks until a stopped walk has returned
+func (f *Finder) Wait() {
+	f.walking.Wait()
+}
+
+// update applies a walk message and reports whether more messages will follow
+func (f *Finder) update(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case finderBatchMsg:
+		if msg.generation != f.generation {
+			return false
+		}
+		// Only the new entries are scored, the earlier ones are already ranked
+		f.index = append(f.index, msg.entries...)
+		f.merge(msg.entries)
+		return true
+
+	case finderDoneMsg:
+		if msg.generation != f.generation {
+			return false
+		}
+		f.indexing = false
+		if msg.err != nil && !errors.Is(msg.err, context.Canceled) {
+			f.err = msg.err
+		}
+	}
+
+	return false
+}
+
+// SetQuery changes the query and filters the index again
+func (f *Finder) SetQuery(query string) {
+	if query == f.query {
+		return
+	}
+	f.query = query
+	f.cursor = 0
+	f.filter()
+}
+
+// MoveCursor moves the selection within the results
+func (f *Finder) MoveCursor(delta int) {
+	f.cursor = max(0, min(f.cursor+delta, len(f.results)-1))
+}
+
+// Selected returns the highlighted result
+func (f *Finder) Selected() *vfsadapter.Entry {
+	if f.cursor >= 0 && f.cursor < len(f.results) {
+		return f.results[f.cursor]
+	}
+	return nil
+}
+
+// finderMatch is an indexed entry that matches the query
+type finderMatch struct {
+	entry *vfsadapter.Entry
+	score int
+}
+
+// filter ranks the whole index against the query and keeps the best matches
+func (f *Finder) filter() {
+	f.matches = nil
+	f.merge(f.index)
+}
+
+// merge ranks entries against the query and merges them into the best matches
+func (f *Finder) merge(entries []*vfsadapter.Entry) {
+	matches := f.matches
+	for _, entry := range entries {
+		if score, ok := fuzzyScore(f.query, entry.Path); ok {
+			matches = append(matches, finderMatch{entry: entry, score: score})
+		}
+	}
+
+	// Stable, so earlier entries stay ahead of equally ranked later ones
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].entry.Path) < len(matches[j].entry.Path)
+	})
+
+	if len(matches) > maxFinderResults {
+		matches = matches[:maxFinderResults]
+	}
+	f.matches = matches
+
+	f.results = make([]*vfsadapter.Entry, 0, len(matches))
+	for _, m := range matches {
+		f.results = append(f.results, m.entry)
+	}
+	if f.cursor >= len(f.results) {
+		f.cursor = max(len(f.results)-1, 0)
+	}
+}
+
+// walk lists the tree breadth-first and passes every listed directory to emit.
+// Depth and entry counts restart at every mount point.
+func (f *Finder) walk(ctx context.Context, adapter *vfsadapter.Adapter, emit func([]*vfsadapter.Entry) bool) error {
+	type pending struct {
+		path  string
+		mount string
+		depth int
+	}
+
+	queue := []pending{{path: "/", mount: "/"}}
+	counts := make(map[string]int)
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		dir := queue[0]
+		queue = queue[1:]
+
+		entries, err := adapter.ListDirectory(dir.path)
+		if err != nil {
+			// Unreadable directories are skipped rather than aborting the whole walk
+			continue
+		}
+
+		batch := make([]*vfsadapter.Entry, 0, len(entries))
+		for _, entry := range entries {
+			mount, depth := dir.mount, dir.depth+1
+			if entry.Mode.IsMount() {
+				mount, depth = entry.Path, 0
+			}
+
+			limit := f.limit(mount)
+			if counts[mount] >= limit.MaxEntries {
+				continue
+			}
+			counts[mount]++
+			batch = append(batch, entry)
+
+			if entry.IsDir && depth < limit.MaxDepth {
+				queue = append(queue, pending{path: entry.Path, mount: mount, depth: depth})
+			}
+		}
+
+		if len(batch) > 0 && !emit(batch) {
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// fuzzyScore reports whether all characters of query appear in order in target.
+// Matches on consecutive characters, word starts and the base name score higher.
+func fuzzyScore(query, target string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(target)
+	base := len(t) - len([]rune(filepath.Base(target)))
+
+	score, qi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if unicode.ToLower(t[ti]) != q[qi] {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			score += 5
+		}
+		if ti == 0 || strings.ContainsRune("/_-. ", t[ti-1]) {
+			score += 3
+		}
+		if ti >= base {
+			score += 2
+		}
+
+		prev = ti
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// SetIndexLimit overrides how deep and how much of the mount at path the fuzzy finder indexes
+func (m *Model) SetIndexLimit(path string, limit IndexLimit) {
+	m.finder.SetLimit(path, limit)
+}
+
+// openFinder shows the fuzzy finder overlay and starts indexing the tree
+func (m *Model) openFinder() tea.Cmd {
+	m.mode = ModeFinder
+	m.finder.SetQuery("")
+	m.textInput.Placeholder = "Search files"
+	m.textInput.SetValue("")
+	m.textInput.Focus()
+
+	return m.finder.Start()
+}
+
+// closeFinder hides the overlay and cancels the walk
+func (m *Model) closeFinder() {
+	m.finder.Stop()
+	m.mode = ModeNormal
+	m.textInput.Blur()
+	m.textInput.SetValue("")
+}
+
+// handleFinderMode processes keys in the fuzzy finder overlay
+func (m *Model) handleFinderMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape, tea.KeyCtrlP:
+		m.closeFinder()
+		return m, nil
+
+	case tea.KeyUp, tea.KeyCtrlK:
+		m.finder.MoveCursor(-1)
+		return m, nil
+
+	case tea.KeyDown, tea.KeyCtrlJ:
+		m.finder.MoveCursor(1)
+		return m, nil
+
+	case tea.KeyEnter:
+		entry := m.finder.Selected()
+		m.closeFinder()
+		if entry == nil {
+			return m, nil
+		}
+
+		// Open the parent directory with the cursor on the chosen entry
+		p := m.pane()
+		p.changeDirectory(filepath.Dir(entry.Path))
+		p.previousDir = entry.Name
+		return m, m.loadDirectory()
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.finder.SetQuery(m.textInput.Value())
+	return m, cmd
+}
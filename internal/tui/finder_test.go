@@ -0,0 +1,59 @@
+package tui
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+		match  bool
+	}{
+		{"", "/data/file.txt", true},
+		{"file", "/data/file.txt", true},
+		{"FILE", "/data/file.txt", true},
+		{"dft", "/data/file.txt", true},
+		{"txt", "/data/file.txt", true},
+		{"tf", "/data/file.txt", true},
+		{"xyz", "/data/file.txt", false},
+		{"fd", "/data/file", false},
+		{"filee", "/data/file", false},
+		{"a", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+" "+tt.target, func(t *testing.T) {
+			if _, ok := fuzzyScore(tt.query, tt.target); ok != tt.match {
+				t.Fatalf("fuzzyScore(%q, %q) matched = %v, want %v", tt.query, tt.target, ok, tt.match)
+			}
+		})
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		better string
+		worse  string
+	}{
+		{"consecutive", "conf", "/etc/config", "/c/o/n/f"},
+		{"base name", "log", "/var/app.log", "/log/app"},
+		{"word start", "d", "/x/data", "/x/odd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, ok := fuzzyScore(tt.query, tt.better)
+			if !ok {
+				t.Fatalf("%q does not match %q", tt.query, tt.better)
+			}
+			worse, ok := fuzzyScore(tt.query, tt.worse)
+			if !ok {
+				t.Fatalf("%q does not match %q", tt.query, tt.worse)
+			}
+			if better <= worse {
+				t.Fatalf("%q scored %d for %q, want more than %d for %q", tt.query, better, tt.better, worse, tt.worse)
+			}
+		})
+	}
+}
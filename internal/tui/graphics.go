@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/png"
+	"os"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/sys/unix"
+)
+
+// GraphicsProtocol is the way images are drawn in the terminal
+type GraphicsProtocol int
+
+const (
+	GraphicsANSI   GraphicsProtocol = iota // Colored half-blocks, works everywhere
+	GraphicsKitty                          // Kitty graphics protocol
+	GraphicsSixel                          // DEC Sixel
+	GraphicsITerm2                         // iTerm2 inline images
+)
+
+const (
+	// Assumed size of a terminal cell in pixels for terminals that do not report it
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+
+	// kittyChunkSize is the largest payload of a single kitty graphics command
+	kittyChunkSize = 4096
+
+	// kittyDeleteImages removes every image placed through the kitty protocol
+	kittyDeleteImages = "\x1b_Ga=d,q=2\x1b\\"
+)
+
+// String returns the name used for the --graphics flag
+func (g GraphicsProtocol) String() string {
+	switch g {
+	case GraphicsKitty:
+		return "kitty"
+	case GraphicsSixel:
+		return "sixel"
+	case GraphicsITerm2:
+		return "iterm2"
+	default:
+		return "ansi"
+	}
+}
+
+// ParseGraphicsProtocol parses a protocol name. "auto" detects the protocol from the environment.
+func ParseGraphicsProtocol(name string) (GraphicsProtocol, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return DetectGraphicsProtocol(), nil
+	case "kitty":
+		return GraphicsKitty, nil
+	case "sixel":
+		return GraphicsSixel, nil
+	case "iterm2":
+		return GraphicsITerm2, nil
+	case "ansi":
+		return GraphicsANSI, nil
+	default:
+		return GraphicsANSI, fmt.Errorf("unknown graphics protocol '%s' (auto, kitty, sixel, iterm2 or ansi)", name)
+	}
+}
+
+// DetectGraphicsProtocol guesses the best protocol from the variables set by common terminals.
+// Terminal multiplexers usually do not pass images through, so they fall back to half-blocks.
+func DetectGraphicsProtocol() GraphicsProtocol {
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return GraphicsANSI
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty", program == "ghostty":
+		return GraphicsKitty
+	case program == "iTerm.app", program == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return GraphicsITerm2
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"), term == "yaft-256color":
+		return GraphicsSixel
+	default:
+		return GraphicsANSI
+	}
+}
+
+// fitCells returns how many columns and rows an image of the given pixel size covers
+// when scaled to fit into cols x rows cells, keeping its aspect ratio
+func fitCells(imgWidth, imgHeight, cols, rows int) (int, int) {
+	if imgWidth <= 0 || imgHeight <= 0 || cols <= 0 || rows <= 0 {
+		return 0, 0
+	}
+
+	// Cells are about twice as high as they are wide
+	scale := min64(float64(cols)/float64(imgWidth), float64(rows*2)/float64(imgHeight))
+	return max(int(float64(imgWidth)*scale), 1), max(int(float64(imgHeight)*scale/2), 1)
+}
+
+// scaleImage resizes img to width x height pixels
+func scaleImage(img image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return dst
+}
+
+// encodeGraphics draws img into cols x rows cells with a pixel protocol.
+// The result is a single line followed by rows-1 empty lines that reserve the space,
+// and the cursor is restored afterwards so the surrounding layout is unaffected.
+func encodeGraphics(img image.Image, protocol GraphicsProtocol, cols, rows int) (string, error) {
+	cellWidth, cellHeight := cellPixelSize()
+	scaled := scaleImage(img, cols*cellWidth, rows*cellHeight)
+
+	var seq string
+	switch protocol {
+	case GraphicsKitty:
+		payload, err := encodePNG(scaled)
+		if err != nil {
+			return "", err
+		}
+		seq = encodeKitty(payload, cols, rows)
+
+	case GraphicsITerm2:
+		payload, err := encodePNG(scaled)
+		if err != nil {
+			return "", err
+		}
+		seq = encodeITerm2(payload, cols, rows)
+
+	case GraphicsSixel:
+		seq = encodeSixel(scaled)
+
+	default:
+		return "", fmt.Errorf("protocol %s cannot draw pixels", protocol)
+	}
+
+	return "\x1b7" + seq + "\x1b8" + strings.Repeat("\n", rows-1), nil
+}
+
+// cellPixelSize returns the size of a terminal cell in pixels as reported by the terminal,
+// or the assumed size if the terminal leaves the pixel size of the window at zero
+func cellPixelSize() (int, int) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return cellPixelWidth, cellPixelHeight
+	}
+
+	width, height := int(ws.Xpixel/ws.Col), int(ws.Ypixel/ws.Row)
+	if width == 0 || height == 0 {
+		return cellPixelWidth, cellPixelHeight
+	}
+	return width, height
+}
+
+// encodePNG returns img as PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeKitty transmits and places a PNG in chunks. The fixed image and placement id
+// make every new preview replace the previous one.
+func encodeKitty(payload []byte, cols, rows int) string {
+	data := base64.StdEncoding.EncodeToString(payload)
+
+	var b strings.Builder
+	for i := 0; i < len(data); i += kittyChunkSize {
+		end := min(i+kittyChunkSize, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,i=1,p=1,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return b.String()
+}
+
+// encodeITerm2 sends a PNG as iTerm2 inline image sized in cells
+func encodeITerm2(payload []byte, cols, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(payload), cols, rows, base64.StdEncoding.EncodeToString(payload))
+}
+
+// encodeSixel dithers img to the web-safe palette and encodes it as sixel bands of six rows
+func encodeSixel(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	paletted := image.NewPaletted(image.Rect(0, 0, width, height), palette.WebSafe)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, bounds.Min)
+
+	var b strings.Builder
+	b.WriteString("\x1bPq")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", width, height)
+
+	for i, c := range paletted.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	for y := 0; y < height; y += 6 {
+		// Only colors that occur in this band are drawn
+		used := make(map[uint8]bool)
+		for dy := 0; dy < 6 && y+dy < height; dy++ {
+			for x := 0; x < width; x++ {
+				used[paletted.ColorIndexAt(x, y+dy)] = true
+			}
+		}
+
+		for index := range used {
+			fmt.Fprintf(&b, "#%d", index)
+
+			run, last := 0, byte(0)
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y+dy < height; dy++ {
+					if paletted.ColorIndexAt(x, y+dy) == index {
+						bits |= 1 << dy
+					}
+				}
+
+				char := '?' + bits
+				if run > 0 && char != last {
+					writeSixelRun(&b, last, run)
+					run = 0
+				}
+				last = char
+				run++
+			}
+			writeSixelRun(&b, last, run)
+
+			// Return to the start of the band for the next color
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRun writes a repeated sixel character, compressing longer runs
+func writeSixelRun(b *strings.Builder, char byte, run int) {
+	if run > 3 {
+		fmt.Fprintf(b, "!%d%c", run, char)
+		return
+	}
+	b.WriteString(strings.Repeat(string(char), run))
+}
+
+// graphicsReset returns the sequence that removes images the kitty protocol keeps
+// on screen until deleted, unless an image preview is currently shown
+func (m *Model) graphicsReset() string {
+	if m.graphics != GraphicsKitty {
+		return ""
+	}
+	if m.tab().showPreview && !m.tab().dualPane && m.tab().previewGraphics {
+		return ""
+	}
+	return kittyDeleteImages
+}
+
+// SetGraphics selects how image previews are drawn
+func (m *Model) SetGraphics(protocol GraphicsProtocol) {
+	m.graphics = protocol
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"strings"
+	"testing"
+)
+
+func TestEncodeKitty(t *testing.T) {
+	// Every 3 payload bytes encode to 4 base64 characters
+	chunkBytes := kittyChunkSize / 4 * 3
+
+	tests := []struct {
+		name   string
+		size   int
+		chunks int
+	}{
+		{"empty", 0, 0},
+		{"single byte", 1, 1},
+		{"exactly one chunk", chunkBytes, 1},
+		{"just over one chunk", chunkBytes + 1, 2},
+		{"several chunks", chunkBytes*3 + 10, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := bytes.Repeat([]byte{0xa5}, tt.size)
+			out := encodeKitty(payload, 40, 12)
+
+			commands := strings.SplitAfter(out, "\x1b\\")
+			commands = commands[:len(commands)-1]
+			if len(commands) != tt.chunks || strings.Join(commands, "") != out {
+				t.Fatalf("encodeKitty(%d bytes) sent %d commands, want %d", tt.size, len(commands), tt.chunks)
+			}
+
+			var data strings.Builder
+			for i, command := range commands {
+				more := 1
+				if i == len(commands)-1 {
+					more = 0
+				}
+
+				prefix := fmt.Sprintf("\x1b_Gm=%d;", more)
+				if i == 0 {
+					prefix = fmt.Sprintf("\x1b_Ga=T,f=100,i=1,p=1,q=2,C=1,c=40,r=12,m=%d;", more)
+				}
+				if !strings.HasPrefix(command, prefix) {
+					t.Fatalf("command %d = %.60q, want prefix %q", i, command, prefix)
+				}
+
+				chunk := strings.TrimSuffix(strings.TrimPrefix(command, prefix), "\x1b\\")
+				if len(chunk) > kittyChunkSize {
+					t.Fatalf("command %d carries %d characters, want at most %d", i, len(chunk), kittyChunkSize)
+				}
+				data.WriteString(chunk)
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(data.String())
+			if err != nil || !bytes.Equal(decoded, payload) {
+				t.Fatalf("chunks do not reassemble into the payload: %v", err)
+			}
+		})
+	}
+}
+
+func TestEncodeSixel(t *testing.T) {
+	red := color.Palette(palette.WebSafe).Index(color.RGBA{R: 0xff, A: 0xff})
+
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		band   string // Expected last bands for a solid red image
+	}{
+		{"single row", 3, 1, fmt.Sprintf("#%d@@@$-", red)},
+		{"compressed run", 4, 1, fmt.Sprintf("#%d!4@$-", red)},
+		{"full band", 5, 6, fmt.Sprintf("#%d!5~$-", red)},
+		{"partial last band", 2, 7, fmt.Sprintf("#%d~~$-#%d@@$-", red, red)},
+		{"several bands", 1, 13, fmt.Sprintf("#%d~$-#%d~$-#%d@$-", red, red, red)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+			draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 0xff, A: 0xff}), image.Point{}, draw.Src)
+
+			out := encodeSixel(img)
+
+			header := fmt.Sprintf("\x1bPq\"1;1;%d;%d", tt.width, tt.height)
+			if !strings.HasPrefix(out, header) || !strings.HasSuffix(out, "\x1b\\") {
+				t.Fatalf("encodeSixel() = %.40q..., want %q framed by the sixel terminator", out, header)
+			}
+
+			// The palette only uses digits and separators, so every '-' ends a band
+			if got, want := strings.Count(out, "-"), (tt.height+5)/6; got != want {
+				t.Fatalf("encodeSixel() wrote %d bands, want %d", got, want)
+			}
+
+			body := strings.TrimSuffix(out, "\x1b\\")
+			if !strings.HasSuffix(body, tt.band) {
+				t.Fatalf("encodeSixel() ends with %q, want %q", body[len(body)-len(tt.band):], tt.band)
+			}
+		})
+	}
+}
+
+func TestWriteSixelRun(t *testing.T) {
+	tests := []struct {
+		run  int
+		want string
+	}{
+		{1, "A"},
+		{3, "AAA"},
+		{4, "!4A"},
+		{120, "!120A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var b strings.Builder
+			writeSixelRun(&b, 'A', tt.run)
+			if got := b.String(); got != tt.want {
+				t.Fatalf("writeSixelRun('A', %d) = %q, want %q", tt.run, got, tt.want)
+			}
+		})
+	}
+}
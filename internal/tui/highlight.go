@@ -0,0 +1,619 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+const (
+	// tabWidth is the number of spaces a tab is expanded to in previews
+	tabWidth = 4
+	// errorContext is how many lines are shown above the line of a parse error
+	errorContext = 3
+)
+
+// tokenKind is the syntax class of a piece of source text
+type tokenKind int
+
+const (
+	tokenPlain tokenKind = iota
+	tokenKeyword
+	tokenType // Builtin types, constants and variables
+	tokenString
+	tokenNumber
+	tokenComment
+	tokenKey // Keys of config formats and attributes of markup
+)
+
+// language describes the lexical rules of a source or config format
+type language struct {
+	keywords      map[string]bool
+	types         map[string]bool
+	ignoreCase    bool     // Keywords match regardless of case (SQL, Dockerfile)
+	lineComments  []string // Prefixes that comment out the rest of the line
+	blockComment  [2]string
+	quotes        string   // Characters that delimit single-line strings
+	multiline     []string // Delimiters of strings that may span lines
+	identChars    string   // Characters allowed in identifiers besides letters, digits and _
+	variable      string   // Prefix of variables, highlighted as types ($ in shell)
+	keyColon      bool     // Words and strings followed by ':' are keys (JSON, YAML)
+	keyEquals     bool     // The first word of a line followed by '=' is a key (TOML, INI)
+	sections      bool     // Lines starting with '[' are section headers (TOML, INI)
+	markup        bool     // Tag names after '<' are keywords, attributes are keys (HTML, XML)
+	commentAtLine bool     // Line comments only start at the beginning of a line (Dockerfile)
+}
+
+// words builds a lookup set from a space separated list
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	cLike = language{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}
+
+	shellLanguage = language{
+		keywords:     words("if then else elif fi for while until do done case esac in function return local export readonly unset shift exit break continue select time source alias"),
+		types:        words("true false echo printf cd test read eval exec set trap"),
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+		multiline:    []string{"`"},
+		variable:     "$",
+		keyEquals:    true,
+	}
+
+	iniLanguage = language{
+		types:        words("true false yes no on off"),
+		lineComments: []string{"#", ";"},
+		quotes:       `"'`,
+		identChars:   "-.",
+		keyEquals:    true,
+		sections:     true,
+	}
+
+	languages = map[string]*language{
+		"text/x-go": withC(language{
+			keywords:  words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var"),
+			types:     words("bool byte complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr any true false nil iota append cap close copy delete len make new panic print println recover min max clear"),
+			multiline: []string{"`"},
+		}),
+		"text/javascript": withC(language{
+			keywords:  words("async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while with yield"),
+			types:     words("true false null undefined NaN Infinity Object Array String Number Boolean Promise Map Set console"),
+			multiline: []string{"`"},
+		}),
+		"text/typescript": withC(language{
+			keywords:  words("abstract as async await break case catch class const continue declare default delete do else enum export extends finally for from function if implements import in instanceof interface keyof let namespace new of private protected public readonly return static super switch this throw try type typeof var void while yield"),
+			types:     words("true false null undefined any unknown never void string number boolean object symbol bigint Array Promise Record"),
+			multiline: []string{"`"},
+		}),
+		"text/x-python": {
+			keywords:     words("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield match case"),
+			types:        words("True False None self int float str bool list dict set tuple bytes object type len print range open super"),
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			multiline:    []string{`"""`, `'''`},
+		},
+		"text/x-java": withC(language{
+			keywords: words("abstract assert break case catch class continue default do else enum extends final finally for if implements import instanceof interface native new package private protected public return static super switch synchronized this throw throws transient try volatile while var record"),
+			types:    words("boolean byte char double float int long short void true false null String Object Integer List Map"),
+		}),
+		"text/x-c": withC(language{
+			keywords: words("auto break case const continue default do else enum extern for goto if inline register restrict return sizeof static struct switch typedef union volatile while #include #define #ifdef #ifndef #endif #if #else #elif #pragma #undef"),
+			types:    words("char double float int long short signed unsigned void bool size_t int8_t int16_t int32_t int64_t uint8_t uint16_t uint32_t uint64_t NULL true false"),
+		}),
+		"text/x-c++": withC(language{
+			keywords: words("auto break case catch class const constexpr continue default delete do else enum explicit extern for friend goto if inline namespace new noexcept operator private protected public return sizeof static struct switch template this throw try typedef typename union using virtual volatile while #include #define #ifdef #ifndef #endif #if #else #elif #pragma"),
+			types:    words("bool char double float int long short signed unsigned void size_t std string vector nullptr true false"),
+		}),
+		"text/x-rust": withC(language{
+			keywords: words("as async await break const continue crate dyn else enum extern fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while"),
+			types:    words("bool char f32 f64 i8 i16 i32 i64 i128 isize u8 u16 u32 u64 u128 usize str String Vec Option Result Some None Ok Err Box true false"),
+		}),
+		"text/x-ruby": {
+			keywords:     words("alias and begin break case class def defined? do else elsif end ensure for if in module next not or redo rescue retry return self super then undef unless until when while yield require"),
+			types:        words("true false nil puts print attr_accessor attr_reader attr_writer"),
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			identChars:   "?!",
+		},
+		"text/x-php": withC(language{
+			keywords:     words("abstract and array as break case catch class clone const continue declare default do echo else elseif empty endforeach endif extends final finally fn for foreach function global if implements include interface isset list namespace new or private protected public require require_once return static switch throw trait try unset use var while yield"),
+			types:        words("true false null TRUE FALSE NULL int float string bool self parent"),
+			lineComments: []string{"//", "#"},
+			variable:     "$",
+		}),
+		"text/x-shellscript": &shellLanguage,
+		"text/x-ini":         &iniLanguage,
+		"application/json": {
+			types:    words("true false null"),
+			quotes:   `"`,
+			keyColon: true,
+		},
+		"application/yaml": {
+			types:        words("true false null yes no on off True False Null ~"),
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			identChars:   "-./",
+			keyColon:     true,
+		},
+		"application/toml": {
+			types:        words("true false inf nan"),
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			multiline:    []string{`"""`, `'''`},
+			identChars:   "-.",
+			keyEquals:    true,
+			sections:     true,
+		},
+		"application/xml": {
+			blockComment: [2]string{"<!--", "-->"},
+			quotes:       `"'`,
+			identChars:   "-:.",
+			markup:       true,
+		},
+		"text/html": {
+			blockComment: [2]string{"<!--", "-->"},
+			quotes:       `"'`,
+			identChars:   "-:",
+			markup:       true,
+		},
+		"text/css": {
+			keywords:     words("@import @media @font-face @keyframes @supports !important"),
+			types:        words("px em rem vh vw auto none inherit initial"),
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       `"'`,
+			identChars:   "-@!",
+			keyColon:     true,
+		},
+		"application/sql": {
+			keywords:     words("select from where and or not insert into values update set delete create table view index drop alter add column primary key foreign references join inner left right outer full on as group by order having limit offset union all distinct case when then else end is null like in between exists begin commit rollback transaction if default unique constraint"),
+			types:        words("int integer bigint smallint text varchar char boolean bool date timestamp real float double numeric decimal blob serial true false"),
+			ignoreCase:   true,
+			lineComments: []string{"--"},
+			blockComment: [2]string{"/*", "*/"},
+			quotes:       `'"`,
+		},
+		"text/x-dockerfile": {
+			keywords:      words("from run cmd label maintainer expose env add copy entrypoint volume user workdir arg onbuild stopsignal healthcheck shell as"),
+			ignoreCase:    true,
+			lineComments:  []string{"#"},
+			quotes:        `"'`,
+			variable:      "$",
+			commentAtLine: true,
+		},
+		"text/x-makefile": {
+			keywords:     words("ifeq ifneq ifdef ifndef else endif include define endef export override .PHONY"),
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			identChars:   "-./",
+			variable:     "$",
+			keyColon:     true,
+		},
+	}
+)
+
+// withC adds the comments and quotes shared by C-like languages to lang
+func withC(lang language) *language {
+	if lang.lineComments == nil {
+		lang.lineComments = cLike.lineComments
+	}
+	lang.blockComment = cLike.blockComment
+	if lang.quotes == "" {
+		lang.quotes = cLike.quotes
+	}
+	return &lang
+}
+
+// detectLanguage returns the syntax of a text preview, or nil for plain text.
+// Scripts without a known type are recognized by their shebang line.
+func detectLanguage(fileType vfsadapter.FileType, name, text string) *language {
+	if lang, ok := languages[fileType.MimeType]; ok {
+		return lang
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ini", ".cfg", ".conf":
+		return &iniLanguage
+	}
+
+	if line, _, _ := strings.Cut(text, "\n"); strings.HasPrefix(line, "#!") {
+		switch {
+		case strings.Contains(line, "python"):
+			return languages["text/x-python"]
+		case strings.Contains(line, "ruby"):
+			return languages["text/x-ruby"]
+		case strings.Contains(line, "node"):
+			return languages["text/javascript"]
+		case strings.Contains(line, "sh"):
+			return &shellLanguage
+		}
+	}
+	return nil
+}
+
+// token is a piece of a line with its syntax class
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// lexer splits text into tokens, carrying open comments and strings across lines
+type lexer struct {
+	lang    *language
+	inBlock string // Closing delimiter of the comment or string spanning lines, if any
+	blockOf tokenKind
+}
+
+// line tokenizes a single line
+func (l *lexer) line(line string) []token {
+	var tokens []token
+	emit := func(kind tokenKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(tokens); n > 0 && tokens[n-1].kind == kind {
+			tokens[n-1].text += text
+			return
+		}
+		tokens = append(tokens, token{kind, text})
+	}
+
+	lang := l.lang
+	rest := line
+
+	// Continue a comment or string opened on a previous line
+	if l.inBlock != "" {
+		end := strings.Index(rest, l.inBlock)
+		if end < 0 {
+			emit(l.blockOf, rest)
+			return tokens
+		}
+		emit(l.blockOf, rest[:end+len(l.inBlock)])
+		rest = rest[end+len(l.inBlock):]
+		l.inBlock = ""
+	}
+
+	if lang.sections && strings.HasPrefix(strings.TrimSpace(rest), "[") {
+		emit(tokenType, rest)
+		return tokens
+	}
+
+	lineStart := true // Only whitespace so far
+	inTag := false
+	for rest != "" {
+		// Comments
+		if l.lineComment(rest, lineStart) {
+			emit(tokenComment, rest)
+			return tokens
+		}
+		if open := lang.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], lang.blockComment[1])
+			if end < 0 {
+				emit(tokenComment, rest)
+				l.inBlock, l.blockOf = lang.blockComment[1], tokenComment
+				return tokens
+			}
+			n := len(open) + end + len(lang.blockComment[1])
+			emit(tokenComment, rest[:n])
+			rest = rest[n:]
+			continue
+		}
+
+		// Strings that may span lines
+		if delim := hasAnyPrefix(rest, lang.multiline); delim != "" {
+			end := strings.Index(rest[len(delim):], delim)
+			if end < 0 {
+				emit(tokenString, rest)
+				l.inBlock, l.blockOf = delim, tokenString
+				return tokens
+			}
+			n := len(delim) + end + len(delim)
+			emit(l.stringKind(rest[:n], rest[n:], lineStart), rest[:n])
+			rest = rest[n:]
+			lineStart = false
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		switch {
+		case strings.ContainsRune(lang.quotes, r):
+			n := stringEnd(rest, r)
+			emit(l.stringKind(rest[:n], rest[n:], lineStart), rest[:n])
+			rest = rest[n:]
+
+		case lang.variable != "" && strings.HasPrefix(rest, lang.variable) && len(rest) > len(lang.variable):
+			n := len(lang.variable)
+			if next := rest[n]; next == '{' || next == '(' {
+				if end := strings.IndexAny(rest[n:], "})"); end >= 0 {
+					n += end + 1
+				} else {
+					n = len(rest)
+				}
+			} else {
+				n += l.identEnd(rest[n:])
+				if n == len(lang.variable) {
+					n++ // Special variables like $? and $1
+				}
+			}
+			emit(tokenType, rest[:min(n, len(rest))])
+			rest = rest[min(n, len(rest)):]
+
+		case unicode.IsDigit(r):
+			n := l.identEnd(rest)
+			emit(tokenNumber, rest[:n])
+			rest = rest[n:]
+
+		case l.isIdentStart(r):
+			n := l.identEnd(rest)
+			word := rest[:n]
+			after := rest[n:]
+			emit(l.wordKind(word, after, lineStart, inTag, tokens), word)
+			rest = after
+
+		default:
+			if lang.markup {
+				switch r {
+				case '<':
+					inTag = true
+				case '>':
+					inTag = false
+				}
+			}
+			if !unicode.IsSpace(r) {
+				lineStart = false
+			}
+			emit(tokenPlain, rest[:size])
+			rest = rest[size:]
+			continue
+		}
+		lineStart = false
+	}
+
+	return tokens
+}
+
+// lineComment reports whether a line comment starts at the beginning of rest
+func (l *lexer) lineComment(rest string, lineStart bool) bool {
+	if l.lang.commentAtLine && !lineStart {
+		return false
+	}
+	return hasAnyPrefix(rest, l.lang.lineComments) != ""
+}
+
+// stringKind returns whether a string is a key or a value, judging by what follows it
+func (l *lexer) stringKind(text, after string, lineStart bool) tokenKind {
+	if l.lang.keyColon && strings.HasPrefix(strings.TrimLeft(after, " \t"), ":") {
+		return tokenKey
+	}
+	if l.lang.keyEquals && lineStart && strings.HasPrefix(strings.TrimLeft(after, " \t"), "=") {
+		return tokenKey
+	}
+	return tokenString
+}
+
+// wordKind classifies an identifier
+func (l *lexer) wordKind(word, after string, lineStart, inTag bool, previous []token) tokenKind {
+	lang := l.lang
+
+	if lang.markup && inTag {
+		// The word right after '<' or '</' is the tag name, the others are attributes
+		if n := len(previous); n > 0 && previous[n-1].kind == tokenPlain {
+			if prev := strings.TrimRight(previous[n-1].text, " \t"); strings.HasSuffix(prev, "<") || strings.HasSuffix(prev, "</") {
+				return tokenKeyword
+			}
+		}
+		return tokenKey
+	}
+
+	// Unlike quoted keys, bare keys need a space after the colon, which keeps URLs and
+	// selectors like a:hover apart from keys
+	trimmed := strings.TrimLeft(after, " \t")
+	if lang.keyColon && (trimmed == ":" || strings.HasPrefix(trimmed, ": ") || strings.HasPrefix(trimmed, ":\t")) {
+		return tokenKey
+	}
+	if lang.keyEquals && lineStart && strings.HasPrefix(trimmed, "=") && !strings.HasPrefix(trimmed, "==") {
+		return tokenKey
+	}
+
+	lookup := word
+	if lang.ignoreCase {
+		lookup = strings.ToLower(word)
+	}
+	switch {
+	case lang.keywords[lookup]:
+		return tokenKeyword
+	case lang.types[lookup]:
+		return tokenType
+	default:
+		return tokenPlain
+	}
+}
+
+// isIdentStart reports whether r may start an identifier
+func (l *lexer) isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_' || (r == '#' && hasHashKeywords(l.lang)) ||
+		(r == '@' && strings.ContainsRune(l.lang.identChars, r))
+}
+
+// identEnd returns the length of the identifier or number at the start of s
+func (l *lexer) identEnd(s string) int {
+	for i, r := range s {
+		if i == 0 && r == '#' {
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && !strings.ContainsRune(l.lang.identChars, r) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+// hasHashKeywords reports whether lang has preprocessor keywords like #include
+func hasHashKeywords(lang *language) bool {
+	return lang.keywords["#include"]
+}
+
+// stringEnd returns the length of the string starting with quote at the start of s,
+// including the closing quote. Unterminated strings end with the line.
+func stringEnd(s string, quote rune) int {
+	escaped := false
+	for i, r := range s {
+		if i == 0 {
+			continue
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == quote:
+			return i + utf8.RuneLen(r)
+		}
+	}
+	return len(s)
+}
+
+// hasAnyPrefix returns the first of prefixes that s starts with
+func hasAnyPrefix(s string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// syntaxStyle returns the theme style for a token class
+func (t *Theme) syntaxStyle(kind tokenKind) (lipgloss.Style, bool) {
+	switch kind {
+	case tokenKeyword:
+		return t.SyntaxKeywordStyle, true
+	case tokenType:
+		return t.SyntaxTypeStyle, true
+	case tokenString:
+		return t.SyntaxStringStyle, true
+	case tokenNumber:
+		return t.SyntaxNumberStyle, true
+	case tokenComment:
+		return t.SyntaxCommentStyle, true
+	case tokenKey:
+		return t.SyntaxKeyStyle, true
+	default:
+		return lipgloss.Style{}, false
+	}
+}
+
+// highlightText renders text with line numbers, coloring it with the syntax of lang
+// if lang is set. Lines are cut to width cells so they do not wrap in the preview.
+func highlightText(text string, lang *language, theme *Theme, width int) string {
+	return highlightSource(text, lang, theme, width, nil, 0)
+}
+
+// highlightSource renders text like highlightText. If perr is set, the output starts a few
+// lines above the failing line, which is marked with a caret pointing at the failing column.
+// A positive maxLines stops once that many lines have been written.
+func highlightSource(text string, lang *language, theme *Theme, width int, perr *parseError, maxLines int) string {
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+
+	digits := len(fmt.Sprint(len(lines)))
+	gutter := digits + 3 // Number, space, bar and space
+	available := 0
+	if width > 0 {
+		available = max(width-gutter, 1)
+	}
+
+	errLine, first := 0, 0
+	if perr != nil {
+		errLine = min(max(perr.line, 1), len(lines))
+		first = max(errLine-1-errorContext, 0)
+	}
+
+	lex := &lexer{lang: lang}
+	var b strings.Builder
+	written := 0
+	for i, raw := range lines {
+		if maxLines > 0 && written >= maxLines {
+			break
+		}
+
+		line := expandTabs(raw)
+		tokens := []token{{tokenPlain, line}}
+		if lang != nil {
+			// Skipped lines are still lexed to track comments and strings spanning lines
+			tokens = lex.line(line)
+		}
+		if i < first {
+			continue
+		}
+
+		if i > first {
+			b.WriteByte('\n')
+		}
+		numberStyle := theme.LineNumberStyle
+		if i+1 == errLine {
+			numberStyle = theme.ErrorStyle
+		}
+		b.WriteString(numberStyle.Render(fmt.Sprintf("%*d │", digits, i+1)))
+		b.WriteByte(' ')
+		writeTokens(&b, tokens, theme, available)
+		written++
+
+		if i+1 == errLine {
+			prefix := []rune(raw)[:min(max(perr.column-1, 0), utf8.RuneCountInString(raw))]
+			caret := utf8.RuneCountInString(expandTabs(string(prefix)))
+			b.WriteByte('\n')
+			b.WriteString(theme.LineNumberStyle.Render(strings.Repeat(" ", digits) + " │"))
+			b.WriteString(" " + strings.Repeat(" ", caret))
+			b.WriteString(theme.ErrorStyle.Render("^ " + perr.msg))
+			written++
+		}
+	}
+	return b.String()
+}
+
+// writeTokens writes tokens styled by their class, cut to width cells unless width is zero
+func writeTokens(b *strings.Builder, tokens []token, theme *Theme, width int) {
+	remaining := width
+	for _, tok := range tokens {
+		if width > 0 && remaining <= 0 {
+			return
+		}
+		text := tok.text
+		if width > 0 {
+			text = truncateRunes(text, remaining)
+			remaining -= utf8.RuneCountInString(text)
+		}
+		if style, ok := theme.syntaxStyle(tok.kind); ok {
+			text = style.Render(text)
+		}
+		b.WriteString(text)
+	}
+}
+
+// expandTabs replaces tabs with spaces so lines can be measured in cells
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", strings.Repeat(" ", tabWidth))
+}
+
+// truncateRunes cuts s to at most n runes. A non-positive n keeps s unchanged.
+func truncateRunes(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
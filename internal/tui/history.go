@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/config"
+)
+
+// historyBack returns the active pane to the previously visited directory
+func (m *Model) historyBack() tea.Cmd {
+	if !m.pane().historyBack() {
+		m.statusMsg = "No previous directory"
+		return nil
+	}
+	return m.loadDirectory()
+}
+
+// historyForward revisits the directory left by the last historyBack
+func (m *Model) historyForward() tea.Cmd {
+	if !m.pane().historyForward() {
+		m.statusMsg = "No next directory"
+		return nil
+	}
+	return m.loadDirectory()
+}
+
+// openJumpList shows the history of the active pane with the current directory selected
+func (m *Model) openJumpList() {
+	_, m.jumpCursor = m.pane().jumpList()
+	m.mode = ModeJumpList
+}
+
+// handleJumpListMode processes keys in the jump list popup
+func (m *Model) handleJumpListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	list, _ := m.pane().jumpList()
+
+	switch {
+	case key.Matches(msg, m.keys.JumpList), key.Matches(msg, m.keys.Quit), msg.Type == tea.KeyEscape:
+		m.mode = ModeNormal
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.jumpCursor > 0 {
+			m.jumpCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.jumpCursor < len(list)-1 {
+			m.jumpCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		m.mode = ModeNormal
+		m.pane().jumpTo(m.jumpCursor)
+		return m, m.loadDirectory()
+	}
+
+	return m, nil
+}
+
+// startBookmarkKey waits for the bookmark letter following m or '
+func (m *Model) startBookmarkKey(prefix string) {
+	m.pendingKey = prefix
+	m.errorMsg = ""
+
+	if prefix == "m" {
+		m.statusMsg = "Set bookmark: press a letter"
+		return
+	}
+
+	bookmarks, err := config.LoadBookmarks(m.bookmarkPath)
+	if err != nil {
+		m.pendingKey = ""
+		m.errorMsg = err.Error()
+		return
+	}
+	if len(bookmarks) == 0 {
+		m.pendingKey = ""
+		m.statusMsg = "No bookmarks set"
+		return
+	}
+
+	names := make([]string, 0, len(bookmarks))
+	for name := range bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m.statusMsg = fmt.Sprintf("Jump to bookmark: %s", strings.Join(names, " "))
+}
+
+// handleBookmarkKey sets or jumps to the bookmark named by msg
+func (m *Model) handleBookmarkKey(msg tea.KeyMsg) tea.Cmd {
+	prefix := m.pendingKey
+	m.pendingKey = ""
+	m.statusMsg = ""
+
+	if msg.Type == tea.KeyEscape {
+		return nil
+	}
+
+	name := msg.String()
+	if m.bookmarkPath == "" {
+		m.errorMsg = "Bookmarks are not available"
+		return nil
+	}
+
+	if prefix == "m" {
+		dir := m.pane().currentPath
+		if err := config.SetBookmark(m.bookmarkPath, name, dir); err != nil {
+			m.errorMsg = err.Error()
+			return nil
+		}
+		m.statusMsg = fmt.Sprintf("Bookmark '%s' set to %s", name, dir)
+		return nil
+	}
+
+	dir, err := config.LookupBookmark(m.bookmarkPath, name)
+	if err != nil {
+		m.errorMsg = err.Error()
+		return nil
+	}
+	if dir != "/" && !isDirectory(m.adapter, dir) {
+		m.errorMsg = fmt.Sprintf("Bookmark '%s' points to missing directory %s", name, dir)
+		return nil
+	}
+
+	m.pane().changeDirectory(dir)
+	return m.loadDirectory()
+}
@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// JobState represents the lifecycle state of a background job
+type JobState int
+
+const (
+	JobRunning JobState = iota
+	JobDone
+	JobFailed
+	JobCanceled
+)
+
+// String returns a short label for the job state
+func (s JobState) String() string {
+	switch s {
+	case JobRunning:
+		return "running"
+	case JobDone:
+		return "done"
+	case JobFailed:
+		return "failed"
+	case JobCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// maxFinishedJobs limits how many finished jobs are kept for the jobs panel
+const maxFinishedJobs = 20
+
+// progressInterval throttles how often progress updates are sent to the UI
+const progressInterval = 100 * time.Millisecond
+
+// JobFunc performs the work of a background job. The adapter is bound to the
+// job's context, so canceling the job aborts any running adapter operation.
+// The returned string is shown in the status bar once the job has finished.
+type JobFunc func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error)
+
+// Job represents a single background operation
+type Job struct {
+	ID          int
+	Description string
+	State       JobState
+	Progress    vfsadapter.CopyProgress
+	Status      string
+	Err         error
+	StartTime   time.Time
+	EndTime     time.Time
+
+	cancel context.CancelFunc
+}
+
+// Percent returns the completion of the job between 0 and 100
+func (j *Job) Percent() int {
+	p := j.Progress
+	switch {
+	case p.TotalBytes > 0:
+		return int(p.Bytes * 100 / p.TotalBytes)
+	case p.TotalFiles > 0:
+		return p.Files * 100 / p.TotalFiles
+	default:
+		return 0
+	}
+}
+
+// ETA estimates the remaining time based on the progress made so far
+func (j *Job) ETA() time.Duration {
+	p := j.Progress
+	elapsed := time.Since(j.StartTime)
+
+	var done, total float64
+	switch {
+	case p.TotalBytes > 0:
+		done, total = float64(p.Bytes), float64(p.TotalBytes)
+	case p.TotalFiles > 0:
+		done, total = float64(p.Files), float64(p.TotalFiles)
+	}
+
+	if done <= 0 || done >= total {
+		return 0
+	}
+	return time.Duration(float64(elapsed) * (total - done) / done).Round(time.Second)
+}
+
+// Messages sent from running jobs
+type jobProgressMsg struct {
+	id       int
+	progress vfsadapter.CopyProgress
+}
+
+type jobFinishedMsg struct {
+	id     int
+	status string
+	err    error
+}
+
+// JobManager runs background jobs and forwards their progress to the TUI
+type JobManager struct {
+	adapter *vfsadapter.Adapter
+	ctx     context.Context // Canceled by Stop once nobody listens for updates anymore
+	stop    context.CancelFunc
+	jobs    []*Job
+	nextID  int
+	updates chan tea.Msg
+	running sync.WaitGroup // Goroutines of jobs that have not returned yet
+}
+
+// NewJobManager creates a new job manager for adapter operations
+func NewJobManager(adapter *vfsadapter.Adapter) *JobManager {
+	ctx, stop := context.WithCancel(adapter.Context())
+	return &JobManager{
+		adapter: adapter,
+		ctx:     ctx,
+		stop:    stop,
+		jobs:    make([]*Job, 0),
+		nextID:  1,
+		updates: make(chan tea.Msg, 64),
+	}
+}
+
+// Listen waits for the next update from any running job
+func (jm *JobManager) Listen() tea.Cmd {
+	return func() tea.Msg {
+		return <-jm.updates
+	}
+}
+
+// Start runs fn in the background and returns the new job
+func (jm *JobManager) Start(description string, fn JobFunc) *Job {
+	ctx, cancel := context.WithCancel(jm.ctx)
+
+	job := &Job{
+		ID:          jm.nextID,
+		Description: description,
+		State:       JobRunning,
+		StartTime:   time.Now(),
+		cancel:      cancel,
+	}
+	jm.nextID++
+	jm.jobs = append(jm.jobs, job)
+	jm.prune()
+
+	jm.running.Add(1)
+	go func() {
+		defer cancel()
+
+		lastReport := time.Time{}
+		report := func(p vfsadapter.CopyProgress) {
+			if time.Since(lastReport) < progressInterval {
+				return
+			}
+			lastReport = time.Now()
+
+			// Drop progress updates rather than blocking the job
+			select {
+			case jm.updates <- jobProgressMsg{id: job.ID, progress: p}:
+			default:
+			}
+		}
+
+		status, err := fn(jm.adapter.WithContext(ctx), report)
+		// The job no longer uses the vfs, even if nobody receives the result anymore
+		jm.running.Done()
+
+		// The job context may be canceled already, only a stopped manager drops the result
+		select {
+		case jm.updates <- jobFinishedMsg{id: job.ID, status: status, err: err}:
+		case <-jm.ctx.Done():
+		}
+	}()
+
+	return job
+}
+
+// Get returns the job with the given id
+func (jm *JobManager) Get(id int) *Job {
+	for _, job := range jm.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// Jobs returns all known jobs, oldest first
+func (jm *JobManager) Jobs() []*Job {
+	return jm.jobs
+}
+
+// Active returns all jobs that are still running
+func (jm *JobManager) Active() []*Job {
+	var active []*Job
+	for _, job := range jm.jobs {
+		if job.State == JobRunning {
+			active = append(active, job)
+		}
+	}
+	return active
+}
+
+// Cancel requests cancellation of a running job
+func (jm *JobManager) Cancel(id int) {
+	if job := jm.Get(id); job != nil && job.State == JobRunning {
+		job.cancel()
+	}
+}
+
+// CancelAll requests cancellation of every running job
+func (jm *JobManager) CancelAll() {
+	for _, job := range jm.Active() {
+		job.cancel()
+	}
+}
+
+// Stop cancels every running job and drops their remaining updates.
+// It is called once the TUI no longer listens for updates.
+func (jm *JobManager) Stop() {
+	jm.stop()
+}
+
+// Wait blocks until the functions of all started jobs have returned
+func (jm *JobManager) Wait() {
+	jm.running.Wait()
+}
+
+// update applies a job message to the matching job
+func (jm *JobManager) update(msg tea.Msg) *Job {
+	switch msg := msg.(type) {
+	case jobProgressMsg:
+		job := jm.Get(msg.id)
+		if job != nil && job.State == JobRunning {
+			job.Progress = msg.progress
+		}
+		return job
+
+	case jobFinishedMsg:
+		job := jm.Get(msg.id)
+		if job == nil {
+			return nil
+		}
+
+		job.EndTime = time.Now()
+		job.Status = msg.status
+		job.Err = msg.err
+
+		switch {
+		case msg.err == nil:
+			job.State = JobDone
+		case errors.Is(msg.err, context.Canceled):
+			job.State = JobCanceled
+		default:
+			job.State = JobFailed
+		}
+		return job
+	}
+
+	return nil
+}
+
+// prune drops the oldest finished jobs once there are too many
+func (jm *JobManager) prune() {
+	finished := 0
+	for _, job := range jm.jobs {
+		if job.State != JobRunning {
+			finished++
+		}
+	}
+
+	kept := jm.jobs[:0]
+	for _, job := range jm.jobs {
+		if job.State != JobRunning && finished > maxFinishedJobs {
+			finished--
+			continue
+		}
+		kept = append(kept, job)
+	}
+	jm.jobs = kept
+}
+
+// Summary returns a one-line summary of the running jobs for the status bar
+func (jm *JobManager) Summary() string {
+	active := jm.Active()
+	switch len(active) {
+	case 0:
+		return ""
+	case 1:
+		job := active[0]
+		summary := fmt.Sprintf("%s %d%%", job.Description, job.Percent())
+		if eta := job.ETA(); eta > 0 {
+			summary += fmt.Sprintf(" ETA %s", eta)
+		}
+		return summary
+	default:
+		return fmt.Sprintf("%d jobs running", len(active))
+	}
+}
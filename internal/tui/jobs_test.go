@@ -0,0 +1,230 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// waitFinished applies updates from jm until job has finished
+func waitFinished(t *testing.T, jm *JobManager, job *Job) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for job.State == JobRunning {
+		select {
+		case msg := <-jm.updates:
+			jm.update(msg)
+		case <-timeout:
+			t.Fatalf("job %d did not finish", job.ID)
+		}
+	}
+}
+
+func TestJobLifecycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     JobFunc
+		cancel bool
+		state  JobState
+		status string
+	}{
+		{
+			name: "done",
+			fn: func(*vfsadapter.Adapter, vfsadapter.ProgressFunc) (string, error) {
+				return "Copied 1 item(s)", nil
+			},
+			state:  JobDone,
+			status: "Copied 1 item(s)",
+		},
+		{
+			name: "failed",
+			fn: func(*vfsadapter.Adapter, vfsadapter.ProgressFunc) (string, error) {
+				return "", errors.New("disk full")
+			},
+			state: JobFailed,
+		},
+		{
+			name: "canceled",
+			fn: func(adapter *vfsadapter.Adapter, _ vfsadapter.ProgressFunc) (string, error) {
+				<-adapter.Context().Done()
+				return "", fmt.Errorf("failed to copy 'a.txt': %w", adapter.Context().Err())
+			},
+			cancel: true,
+			state:  JobCanceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jm := NewJobManager(newTestAdapter(t, nil))
+			defer jm.Stop()
+
+			job := jm.Start(tt.name, tt.fn)
+			if job.State != JobRunning || len(jm.Active()) != 1 {
+				t.Fatalf("Start() = %s job with %d active, want running with 1 active", job.State, len(jm.Active()))
+			}
+
+			if tt.cancel {
+				jm.Cancel(job.ID)
+			}
+			waitFinished(t, jm, job)
+
+			if job.State != tt.state || job.Status != tt.status {
+				t.Fatalf("job finished as %s with %q, want %s with %q", job.State, job.Status, tt.state, tt.status)
+			}
+			if (job.Err != nil) != (tt.state != JobDone) {
+				t.Fatalf("job finished as %s with error %v", job.State, job.Err)
+			}
+			if len(jm.Active()) != 0 || job.EndTime.IsZero() {
+				t.Fatalf("job is still active after finishing")
+			}
+		})
+	}
+}
+
+func TestJobProgress(t *testing.T) {
+	jm := NewJobManager(newTestAdapter(t, nil))
+	defer jm.Stop()
+
+	release := make(chan struct{})
+	job := jm.Start("copy", func(_ *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		report(vfsadapter.CopyProgress{Files: 1, TotalFiles: 4})
+		<-release
+		return "", nil
+	})
+
+	select {
+	case msg := <-jm.updates:
+		jm.update(msg)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("job did not report progress")
+	}
+	if job.Progress.Files != 1 || job.Percent() != 25 {
+		t.Fatalf("progress = %+v at %d%%, want 1 of 4 files at 25%%", job.Progress, job.Percent())
+	}
+
+	close(release)
+	waitFinished(t, jm, job)
+
+	// Progress arriving after the job finished is ignored
+	jm.update(jobProgressMsg{id: job.ID, progress: vfsadapter.CopyProgress{Files: 2, TotalFiles: 4}})
+	if job.Progress.Files != 1 {
+		t.Fatalf("finished job took progress %+v", job.Progress)
+	}
+}
+
+func TestJobManagerStop(t *testing.T) {
+	jm := NewJobManager(newTestAdapter(t, nil))
+
+	// Fill the update channel so the next results can only be dropped
+	for i := 0; i < cap(jm.updates); i++ {
+		jm.updates <- jobProgressMsg{}
+	}
+
+	blocking := func(adapter *vfsadapter.Adapter, _ vfsadapter.ProgressFunc) (string, error) {
+		<-adapter.Context().Done()
+		return "", adapter.Context().Err()
+	}
+	for i := 0; i < 3; i++ {
+		jm.Start("blocking", blocking)
+	}
+
+	jm.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		jm.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Wait() blocked after Stop()")
+	}
+}
+
+func TestJobManagerPrune(t *testing.T) {
+	tests := []struct {
+		name     string
+		finished int
+		running  int
+		kept     int
+		firstID  int
+	}{
+		{"below the limit", 5, 1, 6, 1},
+		{"at the limit", maxFinishedJobs, 0, maxFinishedJobs, 1},
+		{"above the limit", maxFinishedJobs + 5, 2, maxFinishedJobs + 2, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jm := &JobManager{}
+			for i := 0; i < tt.finished+tt.running; i++ {
+				state := JobDone
+				if i >= tt.finished {
+					state = JobRunning
+				}
+				jm.jobs = append(jm.jobs, &Job{ID: i + 1, State: state})
+			}
+
+			jm.prune()
+
+			if len(jm.jobs) != tt.kept || jm.jobs[0].ID != tt.firstID {
+				t.Fatalf("prune() kept %d jobs starting at %d, want %d starting at %d",
+					len(jm.jobs), jm.jobs[0].ID, tt.kept, tt.firstID)
+			}
+			if len(jm.Active()) != tt.running {
+				t.Fatalf("prune() kept %d running jobs, want %d", len(jm.Active()), tt.running)
+			}
+		})
+	}
+}
+
+func TestJobPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress vfsadapter.CopyProgress
+		want     int
+	}{
+		{"no totals", vfsadapter.CopyProgress{Files: 3}, 0},
+		{"bytes", vfsadapter.CopyProgress{Bytes: 512, TotalBytes: 2048, TotalFiles: 1}, 25},
+		{"files without bytes", vfsadapter.CopyProgress{Files: 1, TotalFiles: 2}, 50},
+		{"complete", vfsadapter.CopyProgress{Bytes: 10, TotalBytes: 10}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{Progress: tt.progress}
+			if got := job.Percent(); got != tt.want {
+				t.Fatalf("Percent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobETA(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress vfsadapter.CopyProgress
+		want     time.Duration
+	}{
+		{"nothing done", vfsadapter.CopyProgress{TotalBytes: 100}, 0},
+		{"half done", vfsadapter.CopyProgress{Bytes: 50, TotalBytes: 100}, 10 * time.Second},
+		{"quarter done", vfsadapter.CopyProgress{Files: 1, TotalFiles: 4}, 30 * time.Second},
+		{"complete", vfsadapter.CopyProgress{Bytes: 100, TotalBytes: 100}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{Progress: tt.progress, StartTime: time.Now().Add(-10 * time.Second)}
+			if got := job.ETA(); got != tt.want {
+				t.Fatalf("ETA() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
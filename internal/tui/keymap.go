@@ -14,16 +14,70 @@ type KeyMap struct {
 	Enter     key.Binding
 	Back      key.Binding
 
+	// History
+	HistoryBack    key.Binding
+	HistoryForward key.Binding
+	JumpList       key.Binding
+	SetBookmark    key.Binding
+	JumpBookmark   key.Binding
+	Finder         key.Binding
+
+	// Filter
+	Filter    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+
+	// Sorting
+	SortMode      key.Binding
+	SortReverse   key.Binding
+	SortDirsFirst key.Binding
+
 	// File operations
-	Delete    key.Binding
-	Rename    key.Binding
-	Copy      key.Binding
+	Delete key.Binding
+	Rename key.Binding
+	Copy   key.Binding
+	Cut    key.Binding
+	Paste  key.Binding
+	CopyTo key.Binding
+	MoveTo key.Binding
+	Export key.Binding
+	Import key.Binding
+
+	// Selection
+	Mark      key.Binding
+	Visual    key.Binding
+	SelectAll key.Binding
+	Invert    key.Binding
 	NewFile   key.Binding
 	NewDir    key.Binding
 
 	// View
-	TogglePreview key.Binding
-	Refresh       key.Binding
+	TogglePreview   key.Binding
+	PreviewExpand   key.Binding
+	PreviewCollapse key.Binding
+	RawPreview      key.Binding
+	TogglePanes     key.Binding
+	LongListing     key.Binding
+	TreeView        key.Binding
+	SwitchPane      key.Binding
+	Refresh         key.Binding
+
+	// Tabs
+	NewTab       key.Binding
+	CloseTab     key.Binding
+	NextTab      key.Binding
+	PrevTab      key.Binding
+	MoveTabRight key.Binding
+	MoveTabLeft  key.Binding
+
+	// Jobs
+	Jobs      key.Binding
+	CancelJob key.Binding
+
+	// Search
+	Search        key.Binding
+	SearchResults key.Binding
+	DiskUsage     key.Binding
 
 	// Command mode
 	Command key.Binding
@@ -70,6 +124,46 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("bksp/h", "back"),
 		),
 
+		// History
+		HistoryBack: key.NewBinding(
+			key.WithKeys("H", "alt+left"),
+			key.WithHelp("H", "history back"),
+		),
+		HistoryForward: key.NewBinding(
+			key.WithKeys("L", "alt+right"),
+			key.WithHelp("L", "history forward"),
+		),
+		JumpList: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "jump list"),
+		),
+		SetBookmark: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m<letter>", "set bookmark"),
+		),
+		JumpBookmark: key.NewBinding(
+			key.WithKeys("'"),
+			key.WithHelp("'<letter>", "jump to bookmark"),
+		),
+		Finder: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "find files"),
+		),
+
+		// Filter
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+
 		// File operations
 		Delete: key.NewBinding(
 			key.WithKeys("d", "delete"),
@@ -81,7 +175,49 @@ func DefaultKeyMap() KeyMap {
 		),
 		Copy: key.NewBinding(
 			key.WithKeys("y"),
-			key.WithHelp("y", "copy"),
+			key.WithHelp("y", "yank"),
+		),
+		Cut: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "cut"),
+		),
+		Paste: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "paste"),
+		),
+		CopyTo: key.NewBinding(
+			key.WithKeys("c", "f5"),
+			key.WithHelp("c/f5", "copy to other pane"),
+		),
+		MoveTo: key.NewBinding(
+			key.WithKeys("M", "f6"),
+			key.WithHelp("M/f6", "move to other pane"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "export to host"),
+		),
+		Import: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "import from host"),
+		),
+
+		// Selection
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark"),
+		),
+		Visual: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "range select"),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", "select all"),
+		),
+		Invert: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "invert selection"),
 		),
 		NewFile: key.NewBinding(
 			key.WithKeys("n"),
@@ -97,11 +233,103 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("p"),
 			key.WithHelp("p", "toggle preview"),
 		),
+		PreviewExpand: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "expand preview"),
+		),
+		PreviewCollapse: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "collapse preview"),
+		),
+		RawPreview: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "raw/rendered preview"),
+		),
+		LongListing: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "long listing"),
+		),
+		TreeView: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "tree view"),
+		),
+		TogglePanes: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "dual pane"),
+		),
+		SwitchPane: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch pane"),
+		),
 		Refresh: key.NewBinding(
 			key.WithKeys("ctrl+r"),
 			key.WithHelp("ctrl+r", "refresh"),
 		),
 
+		// Tabs
+		NewTab: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "new tab"),
+		),
+		CloseTab: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", "close tab"),
+		),
+		NextTab: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next tab"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous tab"),
+		),
+		MoveTabRight: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "move tab right"),
+		),
+		MoveTabLeft: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "move tab left"),
+		),
+
+		// Jobs
+		Jobs: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "jobs"),
+		),
+		CancelJob: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "cancel job"),
+		),
+
+		// Sorting
+		SortMode: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "cycle sort mode"),
+		),
+		SortReverse: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "reverse sort"),
+		),
+		SortDirsFirst: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "directories first"),
+		),
+
+		// Search
+		Search: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "search contents"),
+		),
+		SearchResults: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "search results"),
+		),
+		DiskUsage: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "disk usage"),
+		),
+
 		// Command mode
 		Command: key.NewBinding(
 			key.WithKeys("#"),
@@ -129,8 +357,14 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom},
-		{k.Enter, k.Back, k.TogglePreview, k.Refresh},
-		{k.NewFile, k.NewDir, k.Copy, k.Rename, k.Delete},
+		{k.HistoryBack, k.HistoryForward, k.JumpList, k.SetBookmark, k.JumpBookmark, k.Finder},
+		{k.Enter, k.Back, k.TogglePreview, k.PreviewExpand, k.PreviewCollapse, k.RawPreview, k.LongListing, k.TreeView, k.TogglePanes, k.SwitchPane, k.Refresh, k.Jobs},
+		{k.Search, k.SearchResults, k.DiskUsage},
+		{k.NewFile, k.NewDir, k.Copy, k.Cut, k.Paste, k.CopyTo, k.MoveTo, k.Rename, k.Delete, k.Export, k.Import},
+		{k.Mark, k.Visual, k.SelectAll, k.Invert},
+		{k.Filter, k.NextMatch, k.PrevMatch},
+		{k.SortMode, k.SortReverse, k.SortDirsFirst},
+		{k.NewTab, k.CloseTab, k.NextTab, k.PrevTab, k.MoveTabRight, k.MoveTabLeft},
 		{k.Command, k.Help, k.Quit},
 	}
 }
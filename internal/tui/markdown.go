@@ -0,0 +1,662 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// mdStyle is a set of inline Markdown styles
+type mdStyle uint8
+
+const (
+	mdBold mdStyle = 1 << iota
+	mdItalic
+	mdCode
+	mdStrike
+	mdLink
+	mdDim // Link targets and image descriptions
+)
+
+// span is a run of inline text with the same style
+type span struct {
+	text  string
+	style mdStyle
+}
+
+var (
+	mdHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	mdFence     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	mdListItem  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(?:\s+(.*))?$`)
+	mdTaskItem  = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdTableRule = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// fenceLanguages maps the info string of fenced code blocks to the MIME type of their syntax
+var fenceLanguages = map[string]string{
+	"go": "text/x-go", "golang": "text/x-go",
+	"js": "text/javascript", "javascript": "text/javascript",
+	"ts": "text/typescript", "typescript": "text/typescript",
+	"py": "text/x-python", "python": "text/x-python",
+	"java": "text/x-java", "c": "text/x-c", "cpp": "text/x-c++", "c++": "text/x-c++",
+	"rust": "text/x-rust", "rs": "text/x-rust", "ruby": "text/x-ruby", "rb": "text/x-ruby",
+	"php": "text/x-php", "sh": "text/x-shellscript", "bash": "text/x-shellscript",
+	"shell": "text/x-shellscript", "zsh": "text/x-shellscript", "console": "text/x-shellscript",
+	"json": "application/json", "yaml": "application/yaml", "yml": "application/yaml",
+	"toml": "application/toml", "ini": "text/x-ini", "xml": "application/xml",
+	"html": "text/html", "css": "text/css", "sql": "application/sql",
+	"dockerfile": "text/x-dockerfile", "docker": "text/x-dockerfile", "make": "text/x-makefile",
+	"makefile": "text/x-makefile",
+}
+
+// mdRenderer turns Markdown into styled lines of at most width cells
+type mdRenderer struct {
+	theme    *Theme
+	width    int
+	maxLines int // Stop after this many lines, 0 for no limit
+	out      []string
+	inList   bool // The last block was a list item
+}
+
+// renderMarkdown renders Markdown with headings, lists, quotes, code blocks and tables
+// styled with theme. Rendering stops after maxLines lines unless it is zero.
+func renderMarkdown(text string, theme *Theme, width, maxLines int) string {
+	r := &mdRenderer{theme: theme, width: max(width, 20), maxLines: maxLines}
+	r.render(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+	return strings.Join(r.out, "\n")
+}
+
+// full reports whether the line limit has been reached
+func (r *mdRenderer) full() bool {
+	return r.maxLines > 0 && len(r.out) >= r.maxLines
+}
+
+// add appends rendered lines
+func (r *mdRenderer) add(lines ...string) {
+	r.out = append(r.out, lines...)
+}
+
+// gap separates a new block from the previous one with a blank line
+func (r *mdRenderer) gap() {
+	if len(r.out) > 0 && r.out[len(r.out)-1] != "" {
+		r.add("")
+	}
+	r.inList = false
+}
+
+// render renders a sequence of block level lines
+func (r *mdRenderer) render(lines []string) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			r.gap()
+			r.add(r.wrap(parseInline(strings.Join(paragraph, " ")), lipgloss.NewStyle(), r.width, "", "")...)
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines) && !r.full(); i++ {
+		line := expandTabs(lines[i])
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			// HTML comments are not shown
+			flush()
+			for i < len(lines) && !strings.Contains(lines[i], "-->") {
+				i++
+			}
+
+		case mdFence.MatchString(line):
+			flush()
+			match := mdFence.FindStringSubmatch(line)
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), match[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			r.codeBlock(code, strings.ToLower(match[2]))
+
+		case len(paragraph) > 0 && isSetextRule(trimmed):
+			// A rule directly below text underlines it as heading
+			level := 2
+			if trimmed[0] == '=' {
+				level = 1
+			}
+			text := strings.Join(paragraph, " ")
+			paragraph = nil
+			r.heading(level, text)
+
+		case mdHeading.MatchString(line):
+			flush()
+			match := mdHeading.FindStringSubmatch(line)
+			r.heading(len(match[1]), match[2])
+
+		case isThematicBreak(trimmed):
+			flush()
+			r.gap()
+			r.add(r.theme.LineNumberStyle.Render(strings.Repeat("─", r.width)))
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				inner := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(inner, " "))
+			}
+			i--
+			r.quote(quoted)
+
+		case mdListItem.MatchString(line) && (len(paragraph) == 0 || !isOrdered(line)):
+			flush()
+			match := mdListItem.FindStringSubmatch(line)
+			text := match[3]
+			// Continuation lines belong to the item until a blank line or a new block
+			for i+1 < len(lines) {
+				next := expandTabs(lines[i+1])
+				if strings.TrimSpace(next) == "" || mdListItem.MatchString(next) || startsBlock(next) {
+					break
+				}
+				text += " " + strings.TrimSpace(next)
+				i++
+			}
+			r.listItem(len(match[1]), match[2], text)
+
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && mdTableRule.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-"):
+			flush()
+			rows := [][]string{splitTableRow(trimmed)}
+			align := splitTableRow(strings.TrimSpace(lines[i+1]))
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				rows = append(rows, splitTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			r.table(rows, align)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	if r.maxLines > 0 && len(r.out) > r.maxLines {
+		r.out = r.out[:r.maxLines]
+	}
+}
+
+// isSetextRule reports whether line is a row of = or - below a heading
+func isSetextRule(line string) bool {
+	return line != "" && (strings.Trim(line, "=") == "" || strings.Trim(line, "-") == "")
+}
+
+// isThematicBreak reports whether line is a horizontal rule like --- or ***
+func isThematicBreak(line string) bool {
+	line = strings.ReplaceAll(line, " ", "")
+	return len(line) >= 3 && strings.Trim(line, line[:1]) == "" && strings.ContainsAny(line[:1], "-*_")
+}
+
+// isOrdered reports whether line is an ordered list item, which cannot interrupt a paragraph
+func isOrdered(line string) bool {
+	match := mdListItem.FindStringSubmatch(line)
+	return match != nil && unicode.IsDigit(rune(match[2][0]))
+}
+
+// startsBlock reports whether line starts a block that ends a list item or paragraph
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return mdFence.MatchString(line) || mdHeading.MatchString(line) || isThematicBreak(trimmed) ||
+		strings.HasPrefix(trimmed, ">")
+}
+
+// heading renders a heading of level 1 to 6
+func (r *mdRenderer) heading(level int, text string) {
+	style := r.theme.MarkdownH3Style
+	switch level {
+	case 1:
+		style = r.theme.MarkdownH1Style
+	case 2:
+		style = r.theme.MarkdownH2Style
+	}
+
+	r.gap()
+	r.add(r.wrap(parseInline(text), style, r.width, "", "")...)
+	if level == 1 {
+		r.add(r.theme.LineNumberStyle.Render(strings.Repeat("═", min(spansWidth(parseInline(text)), r.width))))
+	}
+}
+
+// codeBlock renders a fenced code block, highlighted if its language is known
+func (r *mdRenderer) codeBlock(code []string, info string) {
+	r.gap()
+
+	var lex *lexer
+	if lang, ok := languages[fenceLanguages[info]]; ok {
+		lex = &lexer{lang: lang}
+	}
+
+	bar := r.theme.LineNumberStyle.Render("│ ")
+	for _, line := range code {
+		line = expandTabs(line)
+		tokens := []token{{tokenPlain, line}}
+		if lex != nil {
+			tokens = lex.line(line)
+		}
+
+		var b strings.Builder
+		b.WriteString(bar)
+		if lex == nil {
+			b.WriteString(r.theme.MarkdownCodeStyle.Render(truncateRunes(line, r.width-2)))
+		} else {
+			writeTokens(&b, tokens, r.theme, r.width-2)
+		}
+		r.add(b.String())
+	}
+}
+
+// quote renders the lines of a block quote, which may hold any other block
+func (r *mdRenderer) quote(lines []string) {
+	inner := &mdRenderer{theme: r.theme, width: r.width - 2}
+	inner.render(lines)
+
+	r.gap()
+	bar := r.theme.MarkdownQuoteStyle.Render("┃ ")
+	for _, line := range inner.out {
+		r.add(bar + line)
+	}
+}
+
+// listItem renders a bullet, numbered or task list item with a hanging indent
+func (r *mdRenderer) listItem(indent int, marker, text string) {
+	if !r.inList {
+		r.gap()
+	}
+
+	level := indent / 2
+	bullet := []string{"•", "◦", "▪"}[level%3]
+	if unicode.IsDigit(rune(marker[0])) {
+		bullet = marker
+	}
+	if match := mdTaskItem.FindStringSubmatch(text); match != nil {
+		bullet = "☐"
+		if match[1] != " " {
+			bullet = "☑"
+		}
+		text = text[len(match[0]):]
+	}
+
+	prefix := strings.Repeat("  ", level) + r.theme.MarkdownListStyle.Render(bullet) + " "
+	hanging := strings.Repeat(" ", level*2+utf8.RuneCountInString(bullet)+1)
+	width := r.width - len(hanging)
+
+	r.add(r.wrap(parseInline(text), lipgloss.NewStyle(), width, prefix, hanging)...)
+	r.inList = true
+}
+
+// splitTableRow splits a table row into its cells, ignoring escaped pipes
+func splitTableRow(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// table renders a table with aligned columns, shrinking the widest columns to fit
+func (r *mdRenderer) table(rows [][]string, align []string) {
+	columns := len(rows[0])
+	cells := make([][][]span, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		cells[i] = make([][]span, columns)
+		for c := 0; c < columns && c < len(row); c++ {
+			cells[i][c] = parseInline(row[c])
+			widths[c] = max(widths[c], spansWidth(cells[i][c]))
+		}
+	}
+
+	// Separators take three cells between columns
+	for total(widths)+3*(columns-1) > r.width {
+		widest := 0
+		for c := range widths {
+			if widths[c] > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	r.gap()
+	separator := r.theme.LineNumberStyle.Render(" │ ")
+	for i := range cells {
+		base := lipgloss.NewStyle()
+		if i == 0 {
+			base = r.theme.SyntaxKeyStyle
+		}
+
+		var parts []string
+		for c := 0; c < columns; c++ {
+			text := r.renderSpans(cells[i][c], base, widths[c])
+			pad := max(widths[c]-min(spansWidth(cells[i][c]), widths[c]), 0)
+
+			a := ""
+			if c < len(align) {
+				a = strings.TrimSpace(align[c])
+			}
+			switch {
+			case strings.HasPrefix(a, ":") && strings.HasSuffix(a, ":"):
+				text = strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2)
+			case strings.HasSuffix(a, ":"):
+				text = strings.Repeat(" ", pad) + text
+			default:
+				text += strings.Repeat(" ", pad)
+			}
+			parts = append(parts, text)
+		}
+		r.add(strings.Join(parts, separator))
+
+		if i == 0 {
+			var rules []string
+			for _, w := range widths {
+				rules = append(rules, strings.Repeat("─", w))
+			}
+			r.add(r.theme.LineNumberStyle.Render(strings.Join(rules, "─┼─")))
+		}
+	}
+}
+
+// total returns the sum of values
+func total(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// spansWidth returns the number of cells the spans take up
+func spansWidth(spans []span) int {
+	width := 0
+	for _, s := range spans {
+		width += utf8.RuneCountInString(s.text)
+	}
+	return width
+}
+
+// spanStyle returns the style of an inline span on top of base
+func (r *mdRenderer) spanStyle(style mdStyle, base lipgloss.Style) lipgloss.Style {
+	s := lipgloss.NewStyle()
+	if style&mdCode != 0 {
+		s = r.theme.MarkdownCodeStyle
+	}
+	if style&mdLink != 0 {
+		s = r.theme.MarkdownLinkStyle
+	}
+	if style&mdDim != 0 {
+		s = r.theme.LineNumberStyle
+	}
+	if style&mdBold != 0 {
+		s = s.Bold(true)
+	}
+	if style&mdItalic != 0 {
+		s = s.Italic(true)
+	}
+	if style&mdStrike != 0 {
+		s = s.Strikethrough(true)
+	}
+	return s.Inherit(base)
+}
+
+// renderSpans renders spans cut to width cells
+func (r *mdRenderer) renderSpans(spans []span, base lipgloss.Style, width int) string {
+	var b strings.Builder
+	remaining := width
+	for _, s := range spans {
+		if remaining <= 0 {
+			break
+		}
+		text := truncateRunes(s.text, remaining)
+		remaining -= utf8.RuneCountInString(text)
+		b.WriteString(r.spanStyle(s.style, base).Render(text))
+	}
+	return b.String()
+}
+
+// wrap breaks spans into lines of at most width cells. The first line starts with prefix,
+// every following line with indent.
+func (r *mdRenderer) wrap(spans []span, base lipgloss.Style, width int, prefix, indent string) []string {
+	width = max(width, 10)
+
+	// Split into words, each made of the styled pieces between spaces
+	var words [][]span
+	var word []span
+	for _, s := range spans {
+		for _, c := range s.text {
+			if c == ' ' {
+				if len(word) > 0 {
+					words = append(words, word)
+					word = nil
+				}
+				continue
+			}
+			if n := len(word); n > 0 && word[n-1].style == s.style {
+				word[n-1].text += string(c)
+			} else {
+				word = append(word, span{string(c), s.style})
+			}
+		}
+	}
+	if len(word) > 0 {
+		words = append(words, word)
+	}
+
+	var lines []string
+	var line []span
+	used := 0
+	emit := func() {
+		lead := indent
+		if len(lines) == 0 {
+			lead = prefix
+		}
+		lines = append(lines, lead+r.renderSpans(line, base, width))
+		line, used = nil, 0
+	}
+
+	for _, w := range words {
+		size := spansWidth(w)
+		if used > 0 && used+1+size > width {
+			emit()
+		}
+		if used > 0 {
+			line = append(line, span{" ", w[0].style &^ mdLink})
+			used++
+		}
+		line = append(line, w...)
+		used += size
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		emit()
+	}
+	return lines
+}
+
+// parseInline splits a line into spans of emphasis, code, links and plain text
+func parseInline(text string) []span {
+	var spans []span
+	var cur strings.Builder
+	style := mdStyle(0)
+
+	flush := func() {
+		if cur.Len() > 0 {
+			spans = append(spans, span{cur.String(), style})
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && unicode.IsPunct(rune(text[i+1])):
+			cur.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			end := strings.Index(rest[ticks:], rest[:ticks])
+			if end >= 0 {
+				flush()
+				spans = append(spans, span{strings.TrimSpace(rest[ticks : ticks+end]), style | mdCode})
+				i += ticks + end + ticks
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			flush()
+			style ^= mdBold
+			i += 2
+			continue
+
+		case strings.HasPrefix(rest, "~~"):
+			flush()
+			style ^= mdStrike
+			i += 2
+			continue
+
+		case c == '*' || c == '_':
+			// Emphasis opens before a word and closes after one, so a * in text stays
+			prevWord := i > 0 && isWordByte(text[i-1])
+			nextWord := i+1 < len(text) && isWordByte(text[i+1])
+			closing := style&mdItalic != 0 && i > 0 && text[i-1] != ' ' && (c == '*' || !nextWord)
+			opening := style&mdItalic == 0 && i+1 < len(text) && text[i+1] != ' ' && (c == '*' || !prevWord)
+			if closing || opening {
+				flush()
+				style ^= mdItalic
+				i++
+				continue
+			}
+
+		case c == '[' || strings.HasPrefix(rest, "!["):
+			image := c == '!'
+			start := 1
+			if image {
+				start = 2
+			}
+			if label, target, n, ok := parseLink(rest, start); ok {
+				flush()
+				if image {
+					spans = append(spans, span{"🖼 " + label, style | mdDim})
+				} else {
+					for _, s := range parseInline(label) {
+						spans = append(spans, span{s.text, s.style | style | mdLink})
+					}
+					if target != "" && target != label && !strings.HasPrefix(target, "#") {
+						spans = append(spans, span{" (" + target + ")", style | mdDim})
+					}
+				}
+				i += n
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && strings.Contains(rest[:end], "://") && !strings.Contains(rest[:end], " ") {
+				flush()
+				spans = append(spans, span{rest[1:end], style | mdLink})
+				i += end + 1
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		cur.WriteString(rest[:size])
+		i += size
+	}
+	flush()
+
+	return spans
+}
+
+// parseLink parses [label](target) or [label][ref] starting at the label text at start.
+// It returns the label, the target and the length of the whole link.
+func parseLink(s string, start int) (string, string, int, bool) {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			label := s[start:i]
+			rest := s[i+1:]
+			switch {
+			case strings.HasPrefix(rest, "("):
+				end := strings.IndexByte(rest, ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				// Drop an optional title after the URL
+				target, _, _ := strings.Cut(strings.TrimSpace(rest[1:end]), " ")
+				return label, strings.Trim(target, "<>"), i + 1 + end + 1, true
+			case strings.HasPrefix(rest, "["):
+				end := strings.IndexByte(rest, ']')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				return label, "", i + 1 + end + 1, true
+			default:
+				return "", "", 0, false
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// isWordByte reports whether c is an ASCII letter or digit
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// generateMarkdownPreview reads a Markdown file and renders it to fit the preview
+func generateMarkdownPreview(a *vfsadapter.Adapter, entry *vfsadapter.Entry, opts PreviewOptions) (string, error) {
+	text, ok, err := a.ReadText(entry.Path, structuredPreviewSize)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "[Binary file - cannot preview as text]", nil
+	}
+
+	maxLines := 0
+	if opts.Height > 0 {
+		// One more line than fits lets the preview show that there is more
+		maxLines = opts.Height + 1
+	}
+	return renderMarkdown(strings.TrimPrefix(text, "\ufeff"), opts.Theme, opts.Width, maxLines), nil
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/mwantia/vfsh/internal/shell"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
 )
 
 // Mode represents the current interaction mode
@@ -21,6 +24,13 @@ const (
 	ModeInput
 	ModeHelp
 	ModeTerminal
+	ModeJobs
+	ModeJumpList
+	ModeFinder
+	ModeFilter
+	ModeSearch
+	ModeUsage
+	ModeReader
 )
 
 // InputType represents what kind of input we're collecting
@@ -32,6 +42,13 @@ const (
 	InputRename
 	InputDelete
 	InputCommand
+	InputPasteConflict
+	InputExport
+	InputImport
+	InputCopyTo
+	InputMoveTo
+	InputSearch
+	InputQuit
 )
 
 // TerminalEntry represents a single command execution in terminal history
@@ -46,25 +63,39 @@ type TerminalEntry struct {
 // Model represents the state of the TUI application
 type Model struct {
 	// Core components
-	adapter *VFSAdapter
+	adapter *vfsadapter.Adapter
+	jobs    *JobManager
+	finder  *Finder
 	theme   *Theme
 	keys    KeyMap
 	help    help.Model
 
-	// Navigation state
-	currentPath string
-	previousDir string // Name of directory we came from (for breadcrumb navigation)
-	entries     []*Entry
-	cursor      int
-	offset      int
+	// Tabs
+	tabs        []*Tab
+	tabIndex    int    // Index of the tab that is shown
+	sessionPath string // File the open tabs are saved to
+
+	// Bookmarks
+	bookmarkPath string // File the bookmarks are shared in with the shell
+	pendingKey   string // Bookmark prefix (m or ') waiting for its letter
+	jumpCursor   int    // Selected entry in the jump list
+
+	// Sorting
+	sorting  *config.SortConfig
+	sortPath string // File the sort order is remembered in
+
+	// Listing
+	listing *config.ListingConfig // Columns of the long listing
+
+	// Pending operations
+	pendingEntries []*vfsadapter.Entry // Entries awaiting confirmation of an operation
+	pendingPaste   *pasteRequest       // Paste awaiting a collision decision
 
 	// View state
-	width          int
-	height         int
-	showPreview    bool
-	previewContent string
-	previewError   error
-	previewGen     int // Generation counter to prevent race conditions
+	width      int
+	height     int
+	previewGen int              // Generation counter to prevent race conditions
+	graphics   GraphicsProtocol // How image previews are drawn
 
 	// Mouse state
 	lastClickTime int64 // Unix nano timestamp of last click
@@ -87,37 +118,95 @@ type Model struct {
 	commandCounter  int // Counter for command numbering
 
 	// Clipboard
-	clipboard string
+	clipboard *Clipboard
+
+	// Jobs
+	jobCursor int // Selected job in the jobs view
+
+	// Search
+	search       *SearchResults // Results of the most recent content search
+	searchCursor int            // Selected match in the search view
+	searchJobID  int            // Job running the most recent search
+
+	// Disk usage
+	usage      *UsageResults // Result of the most recent disk usage walk
+	usageJobID int           // Job running the most recent walk
+
+	// Reader
+	reader *Reader // File shown full-screen
 
 	// Help
 	showFullHelp bool
 }
 
-// NewModel creates a new TUI model
-func NewModel(adapter *VFSAdapter) *Model {
+// NewModel creates a new TUI model that keeps its session, bookmarks, sort order
+// and listing columns in configDir. An empty configDir disables persistence.
+func NewModel(adapter *vfsadapter.Adapter, configDir string) *Model {
 	ti := textinput.New()
 	ti.Placeholder = ""
 	ti.CharLimit = 256
 
-	return &Model{
+	m := &Model{
 		adapter:         adapter,
+		jobs:            NewJobManager(adapter),
+		finder:          NewFinder(adapter),
 		theme:           DefaultTheme(),
 		keys:            DefaultKeyMap(),
 		help:            help.New(),
-		currentPath:     "/",
-		showPreview:     true,
+		tabs:            []*Tab{NewTab("/")},
+		sorting:         config.DefaultSortConfig(),
+		listing:         config.DefaultListingConfig(),
 		textInput:       ti,
 		showFullHelp:    false,
 		terminalHistory: make([]*TerminalEntry, 0),
 		commandCounter:  0,
 		terminalOffset:  0,
 	}
+
+	if configDir == "" {
+		return m
+	}
+
+	m.sessionPath = filepath.Join(configDir, config.SessionFileName)
+	m.bookmarkPath = filepath.Join(configDir, config.BookmarkFileName)
+	m.sortPath = filepath.Join(configDir, config.SortFileName)
+
+	if sorting, err := config.LoadSortConfig(m.sortPath); err != nil {
+		m.errorMsg = err.Error()
+	} else {
+		m.sorting = sorting
+	}
+
+	if listing, err := config.LoadListingConfig(filepath.Join(configDir, config.ListingFileName)); err != nil {
+		m.errorMsg = err.Error()
+	} else {
+		m.listing = listing
+		m.tab().longListing = listing.Long
+	}
+
+	if session, err := config.LoadSession(m.sessionPath); err != nil {
+		m.errorMsg = err.Error()
+	} else {
+		m.restoreSession(session)
+	}
+
+	return m
+}
+
+// Close cancels all background work and waits for it to return,
+// so the vfs can be shut down safely afterwards
+func (m *Model) Close() {
+	m.jobs.Stop()
+	m.finder.Stop()
+	m.jobs.Wait()
+	m.finder.Wait()
 }
 
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
-		m.loadDirectory(),
+		m.loadPanes(),
+		m.jobs.Listen(),
 		textinput.Blink,
 	)
 }
@@ -129,46 +218,127 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.help.Width = msg.Width
+		// The reader renders to the window width
+		if m.mode == ModeReader && m.reader != nil {
+			return m, m.loadReader()
+		}
 		return m, nil
 
 	case directoryLoadedMsg:
-		m.entries = msg.entries
+		// Ignore listings for a directory the pane has already left
+		if msg.pane.currentPath != msg.path {
+			return m, nil
+		}
+
+		entries := msg.entries
+		if msg.pane.tree != nil {
+			msg.pane.tree.children = msg.children
+			msg.pane.tree.children[msg.path] = msg.entries
+			entries = msg.pane.tree.flatten(msg.path)
+		}
+
+		msg.pane.setEntries(entries, m.getPaneLines())
 		m.errorMsg = ""
 
-		// Position cursor on previous directory if we just navigated back
-		if m.previousDir != "" {
-			for i, entry := range m.entries {
-				if entry.Name == m.previousDir {
-					m.cursor = i
-					// Adjust offset to keep cursor visible
-					visibleLines := m.getVisibleLines()
-					if m.cursor >= m.offset+visibleLines {
-						m.offset = m.cursor - visibleLines + 1
-					} else if m.cursor < m.offset {
-						m.offset = m.cursor
-					}
-					break
-				}
-			}
-			m.previousDir = "" // Clear after using
-		} else {
-			// Normal navigation - position at top
-			if len(m.entries) > 0 && m.cursor >= len(m.entries) {
-				m.cursor = len(m.entries) - 1
-			}
-			if m.cursor < 0 {
-				m.cursor = 0
-			}
+		if msg.pane != m.pane() {
+			return m, nil
 		}
 		return m, m.updatePreview()
 
+	case treeLoadedMsg:
+		// Ignore children of a tree the pane has already left
+		if msg.pane.tree == nil || msg.pane.currentPath != msg.root {
+			return m, nil
+		}
+
+		msg.pane.tree.children[msg.dir] = msg.entries
+		msg.pane.rebuildTree(m.getPaneLines())
+
+		if msg.pane != m.pane() {
+			return m, nil
+		}
+		return m, m.updatePreview()
+
+	case previewRequestMsg:
+		// Skip previews the cursor has already moved past
+		if msg.generation != m.previewGen {
+			return m, nil
+		}
+		return m, msg.load
+
 	case previewLoadedMsg:
 		// Only update if this preview is for the current generation
-		if msg.generation == m.previewGen {
-			m.previewContent = msg.content
-			m.previewError = msg.err
+		if msg.generation != m.previewGen {
+			return m, nil
+		}
+
+		// Sixel and iTerm2 have no command to delete an image, it stays on screen until
+		// its cells are erased. Kitty images are deleted by graphicsReset instead.
+		var cmd tea.Cmd
+		if m.tab().previewGraphics && m.graphics != GraphicsKitty {
+			cmd = tea.ClearScreen
 		}
 
+		m.tab().previewContent = msg.content
+		m.tab().previewError = msg.err
+		m.tab().previewGraphics = msg.graphics
+		return m, cmd
+
+	case readerLoadedMsg:
+		// Ignore content for a reader that has been closed or reopened
+		if msg.reader != m.reader {
+			return m, nil
+		}
+
+		lines := strings.Split(strings.TrimRight(msg.content, "\n"), "\n")
+		if msg.content == "" {
+			lines = nil
+		}
+		m.reader.lines = lines
+		m.reader.err = msg.err
+		m.reader.loaded = true
+		m.scrollReader(0)
+		return m, nil
+
+	case jobProgressMsg:
+		m.jobs.update(msg)
+		return m, m.jobs.Listen()
+
+	case jobFinishedMsg:
+		if job := m.jobs.update(msg); job != nil {
+			switch job.State {
+			case JobDone:
+				m.statusMsg = job.Status
+			case JobCanceled:
+				m.statusMsg = fmt.Sprintf("Canceled: %s", job.Description)
+			case JobFailed:
+				m.errorMsg = fmt.Sprintf("%s failed: %v", job.Description, job.Err)
+			}
+
+			// Show the results once a search is complete
+			if job.ID == m.searchJobID && job.State == JobDone && m.mode == ModeNormal {
+				m.mode = ModeSearch
+			}
+			if job.ID == m.usageJobID && job.State == JobDone && m.mode == ModeNormal {
+				m.mode = ModeUsage
+			}
+			if m.usage != nil {
+				m.usage.deleted(job)
+			}
+			// Cut entries no longer exist at their original location once moved
+			if m.clipboard != nil && m.clipboard.jobID == job.ID {
+				m.clipboard.jobID = 0
+				if job.State == JobDone {
+					m.clipboard = nil
+				}
+			}
+		}
+		return m, tea.Batch(m.jobs.Listen(), m.loadPanes())
+
+	case finderBatchMsg, finderDoneMsg:
+		if m.finder.update(msg) {
+			return m, m.finder.Listen()
+		}
 		return m, nil
 
 	case commandExecutedMsg:
@@ -189,7 +359,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// Handle text input updates when in input mode (not terminal, as terminal handles it separately)
-	if m.mode == ModeCommand || m.mode == ModeInput {
+	if m.mode == ModeCommand || m.mode == ModeInput || m.mode == ModeFinder || m.mode == ModeFilter {
 		var cmd tea.Cmd
 		m.textInput, cmd = m.textInput.Update(msg)
 		return m, cmd
@@ -208,6 +378,20 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleHelpMode(msg)
 	case ModeTerminal:
 		return m.handleTerminalMode(msg)
+	case ModeJobs:
+		return m.handleJobsMode(msg)
+	case ModeJumpList:
+		return m.handleJumpListMode(msg)
+	case ModeFinder:
+		return m.handleFinderMode(msg)
+	case ModeFilter:
+		return m.handleFilterMode(msg)
+	case ModeSearch:
+		return m.handleSearchMode(msg)
+	case ModeUsage:
+		return m.handleUsageMode(msg)
+	case ModeReader:
+		return m.handleReaderMode(msg)
 	case ModeNormal:
 		return m.handleNormalMode(msg)
 	}
@@ -217,9 +401,18 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleNormalMode processes keys in normal browsing mode
 func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pendingKey != "" {
+		return m, m.handleBookmarkKey(msg)
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Quit):
-		return m, tea.Quit
+		// Quitting cancels running jobs, so ask first
+		if active := len(m.jobs.Active()); active > 0 {
+			m.startInput(InputQuit, fmt.Sprintf("%d job(s) running. Quit and cancel them? (y/n):", active))
+			return m, nil
+		}
+		return m, m.quit()
 
 	case msg.Type == tea.KeyEscape:
 		// Clear command output if visible (but only if not in terminal mode)
@@ -229,6 +422,14 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusMsg = ""
 			return m, nil
 		}
+		if m.pane().filter != "" {
+			m.clearFilter()
+			return m, m.updatePreview()
+		}
+		if m.pane().hasSelection() {
+			m.pane().clearSelection()
+			return m, nil
+		}
 
 	case key.Matches(msg, m.keys.Help):
 		m.mode = ModeHelp
@@ -252,13 +453,13 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, m.updatePreview()
 
 	case key.Matches(msg, m.keys.Top):
-		m.cursor = 0
-		m.offset = 0
+		m.pane().cursor = 0
+		m.pane().offset = 0
 		return m, m.updatePreview()
 
 	case key.Matches(msg, m.keys.Bottom):
-		if len(m.entries) > 0 {
-			m.cursor = len(m.entries) - 1
+		if len(m.pane().entries) > 0 {
+			m.pane().cursor = len(m.pane().entries) - 1
 		}
 		return m, m.updatePreview()
 
@@ -268,13 +469,91 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Back):
 		return m, m.goBack()
 
+	case key.Matches(msg, m.keys.HistoryBack):
+		return m, m.historyBack()
+
+	case key.Matches(msg, m.keys.HistoryForward):
+		return m, m.historyForward()
+
+	case key.Matches(msg, m.keys.JumpList):
+		m.openJumpList()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Finder):
+		return m, m.openFinder()
+
+	case key.Matches(msg, m.keys.SetBookmark):
+		m.startBookmarkKey("m")
+		return m, nil
+
+	case key.Matches(msg, m.keys.JumpBookmark):
+		m.startBookmarkKey("'")
+		return m, nil
+
 	case key.Matches(msg, m.keys.TogglePreview):
-		m.showPreview = !m.showPreview
+		m.tab().showPreview = !m.tab().showPreview
+		return m, nil
+
+	case key.Matches(msg, m.keys.PreviewExpand):
+		return m, m.changePreviewDepth(1)
+
+	case key.Matches(msg, m.keys.PreviewCollapse):
+		return m, m.changePreviewDepth(-1)
+
+	case key.Matches(msg, m.keys.RawPreview):
+		m.tab().rawPreview = !m.tab().rawPreview
+		if m.tab().rawPreview {
+			m.statusMsg = "Raw preview"
+		} else {
+			m.statusMsg = "Rendered preview"
+		}
+		return m, m.updatePreview()
+
+	case key.Matches(msg, m.keys.TogglePanes):
+		return m, m.toggleDualPane()
+
+	case key.Matches(msg, m.keys.SwitchPane):
+		if m.tab().dualPane {
+			m.tab().active = 1 - m.tab().active
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.NewTab):
+		return m, m.openTab()
+
+	case key.Matches(msg, m.keys.CloseTab):
+		return m, m.closeTab()
+
+	case key.Matches(msg, m.keys.NextTab):
+		return m, m.switchTab(m.tabIndex + 1)
+
+	case key.Matches(msg, m.keys.PrevTab):
+		return m, m.switchTab(m.tabIndex - 1)
+
+	case key.Matches(msg, m.keys.MoveTabRight):
+		m.moveTab(1)
+		return m, nil
+
+	case key.Matches(msg, m.keys.MoveTabLeft):
+		m.moveTab(-1)
 		return m, nil
 
 	case key.Matches(msg, m.keys.Refresh):
 		return m, m.loadDirectory()
 
+	case key.Matches(msg, m.keys.Filter):
+		m.startFilter()
+		return m, nil
+
+	// While a filter is active, n and N cycle through its matches instead of creating entries
+	case m.pane().filter != "" && key.Matches(msg, m.keys.NextMatch):
+		m.pane().cycleMatch(1, m.getPaneLines())
+		return m, m.updatePreview()
+
+	case m.pane().filter != "" && key.Matches(msg, m.keys.PrevMatch):
+		m.pane().cycleMatch(-1, m.getPaneLines())
+		return m, m.updatePreview()
+
 	case key.Matches(msg, m.keys.NewFile):
 		m.startInput(InputNewFile, "New file name:")
 		return m, nil
@@ -284,25 +563,114 @@ func (m *Model) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, m.keys.Delete):
-		if m.currentEntry() != nil {
-			m.startInput(InputDelete, fmt.Sprintf("Delete %s? (y/n):", m.currentEntry().Name))
+		if entries := m.pane().selectedEntries(); len(entries) > 0 {
+			m.startInput(InputDelete, fmt.Sprintf("Delete %s? (y/n):", describeSelection(entries)))
+			m.pendingEntries = entries
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Export):
+		if entries := m.pane().selectedEntries(); len(entries) > 0 {
+			m.startInput(InputExport, fmt.Sprintf("Export %s to host directory:", describeSelection(entries)))
+			m.pendingEntries = entries
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.Import):
+		m.startInput(InputImport, "Import host path into current directory:")
+		return m, nil
+
+	case key.Matches(msg, m.keys.Jobs):
+		m.mode = ModeJobs
+		m.jobCursor = 0
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search):
+		m.startInput(InputSearch, fmt.Sprintf("Search file contents below %s (re: for regex):", m.pane().currentPath))
+		return m, nil
+
+	case key.Matches(msg, m.keys.SearchResults):
+		m.mode = ModeSearch
+		return m, nil
+
+	case key.Matches(msg, m.keys.DiskUsage):
+		return m, m.startUsage()
+
+	case key.Matches(msg, m.keys.TreeView):
+		return m, m.toggleTreeView()
+
+	case key.Matches(msg, m.keys.LongListing):
+		m.toggleLongListing()
+		return m, nil
+
+	case key.Matches(msg, m.keys.SortMode):
+		return m, m.changeSort(config.SortSettings.Next)
+
+	case key.Matches(msg, m.keys.SortReverse):
+		return m, m.changeSort(func(s config.SortSettings) config.SortSettings {
+			s.Descending = !s.Descending
+			return s
+		})
+
+	case key.Matches(msg, m.keys.SortDirsFirst):
+		return m, m.changeSort(func(s config.SortSettings) config.SortSettings {
+			s.DirsFirst = !s.DirsFirst
+			return s
+		})
+
+	case key.Matches(msg, m.keys.Mark):
+		m.pane().toggleMark()
+		m.moveCursor(1)
+		return m, m.updatePreview()
+
+	case key.Matches(msg, m.keys.Visual):
+		m.pane().toggleVisual()
+		return m, nil
+
+	case key.Matches(msg, m.keys.SelectAll):
+		m.pane().selectAll()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Invert):
+		m.pane().invertSelection()
+		return m, nil
+
 	case key.Matches(msg, m.keys.Rename):
-		if m.currentEntry() != nil {
+		if entry := m.pane().currentEntry(); entry != nil {
 			m.startInput(InputRename, "New name:")
-			m.textInput.SetValue(m.currentEntry().Name)
+			m.textInput.SetValue(entry.Name)
 		}
 		return m, nil
 
 	case key.Matches(msg, m.keys.Copy):
-		if entry := m.currentEntry(); entry != nil {
-			m.clipboard = entry.Path
-			m.statusMsg = fmt.Sprintf("Copied: %s", entry.Name)
+		m.setClipboard(ClipboardCopy, m.pane().selectedEntries())
+		m.pane().clearSelection()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Cut):
+		m.setClipboard(ClipboardCut, m.pane().selectedEntries())
+		m.pane().clearSelection()
+		return m, nil
+
+	case key.Matches(msg, m.keys.CopyTo):
+		if entries := m.pane().selectedEntries(); len(entries) > 0 {
+			m.startInput(InputCopyTo, fmt.Sprintf("Copy %s to:", describeSelection(entries)))
+			m.textInput.SetValue(m.targetDirectory())
+			m.pendingEntries = entries
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.MoveTo):
+		if entries := m.pane().selectedEntries(); len(entries) > 0 {
+			m.startInput(InputMoveTo, fmt.Sprintf("Move %s to:", describeSelection(entries)))
+			m.textInput.SetValue(m.targetDirectory())
+			m.pendingEntries = entries
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Paste):
+		return m, m.startPaste()
+
 	case key.Matches(msg, m.keys.Command):
 		// Toggle between Navigation and Terminal modes
 		if m.mode == ModeTerminal {
@@ -356,6 +724,37 @@ func (m *Model) handleHelpMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleJobsMode processes keys in the jobs view
+func (m *Model) handleJobsMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	jobs := m.jobs.Jobs()
+
+	switch {
+	case key.Matches(msg, m.keys.Jobs), key.Matches(msg, m.keys.Quit), msg.Type == tea.KeyEscape:
+		m.mode = ModeNormal
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.jobCursor > 0 {
+			m.jobCursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		if m.jobCursor < len(jobs)-1 {
+			m.jobCursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.CancelJob):
+		if m.jobCursor >= 0 && m.jobCursor < len(jobs) {
+			m.jobs.Cancel(jobs[m.jobCursor].ID)
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
 // handleTerminalMode processes keys in terminal mode
 func (m *Model) handleTerminalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
@@ -430,9 +829,22 @@ func (m *Model) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			return m, m.updatePreview()
 
 		case tea.MouseButtonLeft:
+			// Clicking into the other pane focuses it
+			if m.tab().dualPane {
+				m.tab().active = 0
+				if msg.X >= m.width/2 {
+					m.tab().active = 1
+				}
+			}
+			p := m.pane()
+
 			// Calculate which file entry was clicked
 			// Title bar (1 line) + border top (1 line) = 2 lines before first entry
 			m.fileListTop = 2
+			if m.tab().dualPane {
+				// Each pane starts with its path header
+				m.fileListTop++
+			}
 
 			if msg.Y < m.fileListTop {
 				// Click was in title area, ignore
@@ -441,27 +853,27 @@ func (m *Model) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 			// Calculate which entry was clicked
 			clickedLine := msg.Y - m.fileListTop
-			clickedIndex := m.offset + clickedLine
+			clickedIndex := p.offset + clickedLine
 
-			if clickedIndex >= 0 && clickedIndex < len(m.entries) {
+			if clickedIndex >= 0 && clickedIndex < len(p.entries) {
 				// Check for double-click (within 500ms and same position)
 				now := time.Now().UnixNano()
 				doubleClickThreshold := int64(500 * time.Millisecond)
 
 				isDoubleClick := (now-m.lastClickTime) < doubleClickThreshold &&
 					msg.Y == m.lastClickY &&
-					clickedIndex == m.cursor
+					clickedIndex == p.cursor
 
 				m.lastClickTime = now
 				m.lastClickY = msg.Y
 
 				if isDoubleClick {
 					// Double-click: enter directory
-					m.cursor = clickedIndex
+					p.cursor = clickedIndex
 					return m, m.enterDirectory()
 				} else {
 					// Single click: select item
-					m.cursor = clickedIndex
+					p.cursor = clickedIndex
 					return m, m.updatePreview()
 				}
 			}
@@ -526,7 +938,24 @@ func (m *Model) submitInput() tea.Cmd {
 		return m.renameEntry(value)
 	case InputDelete:
 		if strings.ToLower(value) == "y" || strings.ToLower(value) == "yes" {
-			return m.deleteEntry()
+			return m.deleteEntries(m.pendingEntries)
+		}
+		return nil
+	case InputPasteConflict:
+		return m.submitPasteConflict(value)
+	case InputExport:
+		return m.exportEntries(m.pendingEntries, value)
+	case InputImport:
+		return m.importPath(value)
+	case InputCopyTo:
+		return m.transferEntries(ClipboardCopy, m.pendingEntries, value)
+	case InputMoveTo:
+		return m.transferEntries(ClipboardCut, m.pendingEntries, value)
+	case InputSearch:
+		return m.startSearch(value)
+	case InputQuit:
+		if strings.ToLower(value) == "y" || strings.ToLower(value) == "yes" {
+			return m.quit()
 		}
 		return nil
 	}
@@ -534,30 +963,46 @@ func (m *Model) submitInput() tea.Cmd {
 	return nil
 }
 
-// moveCursor moves the cursor by delta, handling bounds and scrolling
-func (m *Model) moveCursor(delta int) {
-	if len(m.entries) == 0 {
-		return
-	}
+// quit cancels all background work and exits the program
+func (m *Model) quit() tea.Cmd {
+	m.jobs.CancelAll()
+	m.finder.Stop()
+	return tea.Quit
+}
 
-	m.cursor += delta
+// pane returns the pane that currently receives input
+func (m *Model) pane() *Pane {
+	return m.tab().panes[m.tab().active]
+}
 
-	// Clamp cursor
-	if m.cursor < 0 {
-		m.cursor = 0
-	}
-	if m.cursor >= len(m.entries) {
-		m.cursor = len(m.entries) - 1
-	}
+// otherPane returns the inactive pane
+func (m *Model) otherPane() *Pane {
+	return m.tab().panes[1-m.tab().active]
+}
 
-	// Adjust offset for scrolling
-	visibleLines := m.getVisibleLines()
-	if m.cursor < m.offset {
-		m.offset = m.cursor
+// toggleDualPane switches between the single and the dual-pane layout
+func (m *Model) toggleDualPane() tea.Cmd {
+	m.tab().dualPane = !m.tab().dualPane
+	if !m.tab().dualPane {
+		return m.updatePreview()
 	}
-	if m.cursor >= m.offset+visibleLines {
-		m.offset = m.cursor - visibleLines + 1
+
+	// The other pane may have been hidden since it was last loaded
+	return m.loadPane(m.otherPane())
+}
+
+// targetDirectory returns the default destination for copy and move,
+// which is the other pane's directory in dual-pane mode
+func (m *Model) targetDirectory() string {
+	if m.tab().dualPane {
+		return m.otherPane().workingDirectory()
 	}
+	return m.pane().currentPath
+}
+
+// moveCursor moves the cursor of the active pane by delta
+func (m *Model) moveCursor(delta int) {
+	m.pane().moveCursor(delta, m.getPaneLines())
 }
 
 // getVisibleLines returns how many file entries can be displayed
@@ -572,23 +1017,37 @@ func (m *Model) getVisibleLines() int {
 	return available
 }
 
-// currentEntry returns the currently selected entry
-func (m *Model) currentEntry() *Entry {
-	if m.cursor >= 0 && m.cursor < len(m.entries) {
-		return m.entries[m.cursor]
+// getPaneLines returns how many entries fit into a file list,
+// leaving room for the path header shown in dual-pane mode
+func (m *Model) getPaneLines() int {
+	if m.tab().dualPane {
+		return m.getVisibleLines() - 1
 	}
-	return nil
+	return m.getVisibleLines()
 }
 
 // Messages for async operations
 type directoryLoadedMsg struct {
-	entries []*Entry
+	pane     *Pane
+	path     string
+	entries  []*vfsadapter.Entry
+	children map[string][]*vfsadapter.Entry // Listings of expanded directories in tree view
+}
+
+// previewDelay is how long the cursor has to rest on a file before its preview is loaded
+const previewDelay = 80 * time.Millisecond
+
+// previewRequestMsg starts loading a preview once the cursor has rested for previewDelay
+type previewRequestMsg struct {
+	generation int
+	load       tea.Cmd
 }
 
 type previewLoadedMsg struct {
 	content    string
 	err        error
-	generation int // Which preview request this is for
+	generation int  // Which preview request this is for
+	graphics   bool // Content draws an image with a pixel protocol
 }
 
 type commandExecutedMsg struct {
@@ -600,21 +1059,51 @@ type errorMsg string
 
 // Commands for async operations
 func (m *Model) loadDirectory() tea.Cmd {
+	return m.loadPane(m.pane())
+}
+
+func (m *Model) loadPane(p *Pane) tea.Cmd {
+	path := p.currentPath
+	settings := m.sortSettings(path)
+	expanded := p.expandedDirs()
+
 	return func() tea.Msg {
-		entries, err := m.adapter.ListDirectory(m.currentPath)
+		entries, err := m.adapter.ListDirectory(path)
 		if err != nil {
 			return errorMsg(fmt.Sprintf("Failed to load directory: %v", err))
 		}
-		return directoryLoadedMsg{entries: entries}
+		m.adapter.SniffEntries(entries)
+		sortEntries(entries, settings)
+
+		// Expanded directories that can no longer be listed are collapsed
+		children := make(map[string][]*vfsadapter.Entry, len(expanded))
+		for _, dir := range expanded {
+			if list, err := m.adapter.ListDirectory(dir); err == nil {
+				m.adapter.SniffEntries(list)
+				sortEntries(list, settings)
+				children[dir] = list
+			}
+		}
+
+		return directoryLoadedMsg{pane: p, path: path, entries: entries, children: children}
 	}
 }
 
+// loadPanes reloads every visible pane
+func (m *Model) loadPanes() tea.Cmd {
+	if !m.tab().dualPane {
+		return m.loadDirectory()
+	}
+	return tea.Batch(m.loadPane(m.tab().panes[0]), m.loadPane(m.tab().panes[1]))
+}
+
 func (m *Model) updatePreview() tea.Cmd {
-	if !m.showPreview {
+	// The preview is replaced by the second file list in dual-pane mode
+	if !m.tab().showPreview || m.tab().dualPane {
 		return nil
 	}
 
-	entry := m.currentEntry()
+	entry := m.pane().currentEntry()
 
 	// Increment generation counter for new preview
 	m.previewGen++
@@ -629,116 +1118,206 @@ func (m *Model) updatePreview() tea.Cmd {
 	// Capture entry path to prevent race
 	entryPath := entry.Path
 
-	return func() tea.Msg {
-		// Calculate available space for preview
-		previewWidth := m.width / 2
-		previewHeight := m.height - 10
+	// Calculate available space for preview, matching the box and padding in renderContent
+	opts := PreviewOptions{
+		Width:    m.width - m.width/2 - 8,
+		Height:   m.getVisibleLines() - 6,
+		Graphics: m.graphics,
+		Theme:    m.theme,
+		Depth:    m.tab().previewDepth,
+		Raw:      m.tab().rawPreview,
+	}
+	// The copy is sniffed in the background, the listed entry is only touched by Update
+	sniffed := *entry
 
+	load := func() tea.Msg {
 		// Use new preview system that handles different file types
-		content, err := m.adapter.GeneratePreview(entryPath, previewWidth, previewHeight)
+		content, err := GeneratePreview(m.adapter, entryPath, opts)
+		// GeneratePreview picks the preview by the sniffed type, which is cached by now
+		graphics := opts.Graphics != GraphicsANSI && m.adapter.SniffFileType(&sniffed).Preview() == vfsadapter.PreviewImage
+
+		return previewLoadedMsg{content: content, err: err, generation: currentGen, graphics: graphics}
+	}
+
+	// Reading and highlighting only starts once the cursor rests, so scrolling
+	// quickly through a directory does not queue up a preview for every entry
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewRequestMsg{generation: currentGen, load: load}
+	})
+}
 
-		return previewLoadedMsg{content: content, err: err, generation: currentGen}
+// changePreviewDepth expands or collapses one more level of structured previews
+func (m *Model) changePreviewDepth(delta int) tea.Cmd {
+	depth := min(max(m.tab().previewDepth+delta, 1), maxPreviewDepth)
+	if depth == m.tab().previewDepth {
+		return nil
 	}
+
+	m.tab().previewDepth = depth
+	m.statusMsg = fmt.Sprintf("Preview depth: %d", depth)
+	return m.updatePreview()
 }
 
 func (m *Model) enterDirectory() tea.Cmd {
-	entry := m.currentEntry()
+	entry := m.pane().currentEntry()
 	if entry == nil {
 		return nil
 	}
 
+	// Archives open as read-only directories, other files in the reader
+	if entry.IsBrowsableArchive() {
+		m.pane().changeDirectory(entry.Path)
+		m.statusMsg = fmt.Sprintf("Browsing %s read-only, copy members out to extract them", entry.Name)
+		return m.loadDirectory()
+	}
 	if !entry.IsDir {
-		m.statusMsg = fmt.Sprintf("Cannot open file: %s", entry.Name)
-		return nil
+		return m.openReader(entry)
 	}
 
-	m.currentPath = entry.Path
-	m.previousDir = "" // Clear previous directory when entering new one
-	m.cursor = 0
-	m.offset = 0
+	// Directories expand and collapse in place in tree view
+	if m.pane().tree != nil {
+		return m.toggleNode(entry)
+	}
 
+	m.pane().changeDirectory(entry.Path)
 	return m.loadDirectory()
 }
 
 func (m *Model) goBack() tea.Cmd {
-	if m.currentPath == "/" {
-		return nil
+	if m.pane().tree != nil && m.collapseNode() {
+		return m.updatePreview()
 	}
 
 	// Remember which directory we're leaving so we can position cursor on it
-	m.previousDir = filepath.Base(m.currentPath)
-
-	m.currentPath = filepath.Dir(m.currentPath)
-	m.cursor = 0
-	m.offset = 0
+	if !m.pane().parentDirectory() {
+		return nil
+	}
 	return m.loadDirectory()
 }
 
 func (m *Model) createFile(name string) tea.Cmd {
+	dir := m.pane().workingDirectory()
+	reload := m.loadDirectory() // Captures the pane here, not in the command's goroutine
+
 	return func() tea.Msg {
-		path := filepath.Join(m.currentPath, name)
+		path := filepath.Join(dir, name)
 		if err := m.adapter.CreateFile(path); err != nil {
 			return errorMsg(fmt.Sprintf("Failed to create file: %v", err))
 		}
-		return m.loadDirectory()()
+		return reload()
 	}
 }
 
 func (m *Model) createDirectory(name string) tea.Cmd {
+	dir := m.pane().workingDirectory()
+	reload := m.loadDirectory()
+
 	return func() tea.Msg {
-		path := filepath.Join(m.currentPath, name)
+		path := filepath.Join(dir, name)
 		if err := m.adapter.CreateDirectory(path); err != nil {
 			return errorMsg(fmt.Sprintf("Failed to create directory: %v", err))
 		}
-		return m.loadDirectory()()
+		return reload()
 	}
 }
 
-func (m *Model) deleteEntry() tea.Cmd {
-	entry := m.currentEntry()
-	if entry == nil {
+func (m *Model) deleteEntries(entries []*vfsadapter.Entry) tea.Cmd {
+	if len(entries) == 0 {
 		return nil
 	}
+	m.pendingEntries = nil
 
-	return func() tea.Msg {
-		var err error
-		if entry.IsDir {
-			err = m.adapter.DeleteRecursive(entry.Path)
-		} else {
-			err = m.adapter.Delete(entry.Path, false)
+	m.startDelete(entries)
+	return nil
+}
+
+// startDelete starts a job that deletes entries, directories recursively
+func (m *Model) startDelete(entries []*vfsadapter.Entry) *Job {
+	return m.startJob(fmt.Sprintf("Delete %d item(s)", len(entries)), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		progress := vfsadapter.CopyProgress{TotalFiles: len(entries)}
+
+		for _, entry := range entries {
+			if err := adapter.Context().Err(); err != nil {
+				return "", err
+			}
+
+			progress.Path = entry.Path
+			report(progress)
+
+			var err error
+			if entry.IsDir {
+				err = adapter.DeleteRecursive(entry.Path)
+			} else {
+				err = adapter.Delete(entry.Path, false)
+			}
+
+			if err != nil {
+				return "", fmt.Errorf("failed to delete %s: %v", entry.Name, err)
+			}
+			progress.Files++
+		}
+
+		return fmt.Sprintf("Deleted %d item(s)", len(entries)), nil
+	})
+}
+
+func (m *Model) exportEntries(entries []*vfsadapter.Entry, hostDir string) tea.Cmd {
+	if len(entries) == 0 {
+		return nil
+	}
+	m.pendingEntries = nil
+
+	m.startJob(fmt.Sprintf("Export %d item(s)", len(entries)), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		for _, entry := range entries {
+			if _, err := adapter.Export(entry.Path, hostDir, report); err != nil {
+				return "", err
+			}
 		}
 
+		return fmt.Sprintf("Exported %d item(s) to %s", len(entries), hostDir), nil
+	})
+
+	return nil
+}
+
+func (m *Model) importPath(hostPath string) tea.Cmd {
+	targetDir := m.pane().workingDirectory()
+
+	m.startJob(fmt.Sprintf("Import %s", filepath.Base(hostPath)), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		summary, err := adapter.Import(hostPath, targetDir, report)
 		if err != nil {
-			return errorMsg(fmt.Sprintf("Failed to delete: %v", err))
+			return "", err
 		}
-		return m.loadDirectory()()
-	}
+		return fmt.Sprintf("Imported %s", summary), nil
+	})
+
+	return nil
+}
+
+// startJob runs fn as a background job and reports it in the status bar
+func (m *Model) startJob(description string, fn JobFunc) *Job {
+	job := m.jobs.Start(description, fn)
+	m.statusMsg = fmt.Sprintf("Started: %s", description)
+	return job
 }
 
 func (m *Model) renameEntry(newName string) tea.Cmd {
-	entry := m.currentEntry()
+	entry := m.pane().currentEntry()
 	if entry == nil {
 		return nil
 	}
 
-	return func() tea.Msg {
-		// For now, we'll implement rename as copy + delete
-		// since VFS doesn't have Rename implemented yet
-		newPath := filepath.Join(m.currentPath, newName)
-
-		if !entry.IsDir {
-			if err := m.adapter.CopyFile(entry.Path, newPath); err != nil {
-				return errorMsg(fmt.Sprintf("Failed to rename: %v", err))
-			}
-			if err := m.adapter.Delete(entry.Path, false); err != nil {
-				return errorMsg(fmt.Sprintf("Failed to remove old file: %v", err))
-			}
-		} else {
-			return errorMsg("Directory rename not yet supported")
+	// Renames across mounts copy the entry, which can take a while
+	oldPath := entry.Path
+	newPath := filepath.Join(filepath.Dir(oldPath), newName)
+	m.startJob(fmt.Sprintf("Rename %s", entry.Name), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		if err := adapter.Move(oldPath, newPath, report); err != nil {
+			return "", fmt.Errorf("failed to rename: %v", err)
 		}
+		return fmt.Sprintf("Renamed to %s", newName), nil
+	})
 
-		return m.loadDirectory()()
-	}
+	return nil
 }
 
 // submitTerminalCommand executes a command in terminal mode
@@ -753,7 +1332,7 @@ func (m *Model) submitTerminalCommand() tea.Cmd {
 	// Create terminal entry with current path
 	entry := &TerminalEntry{
 		Number:  m.commandCounter,
-		Path:    m.currentPath,
+		Path:    m.pane().currentPath,
 		Command: cmdLine,
 	}
 
@@ -763,7 +1342,7 @@ func (m *Model) submitTerminalCommand() tea.Cmd {
 
 	return func() tea.Msg {
 		// Parse command line
-		args := parseCommandLine(cmdLine)
+		args := shell.ParseCommandLine(cmdLine)
 		if len(args) == 0 {
 			return commandExecutedMsg{output: "", error: ""}
 		}
@@ -771,7 +1350,7 @@ func (m *Model) submitTerminalCommand() tea.Cmd {
 		// Create a buffer to capture command output
 		var buf strings.Builder
 
-		exitCode, err := m.adapter.vfs.Execute(m.adapter.ctx, &buf, args...)
+		exitCode, err := m.adapter.Execute(&buf, args...)
 
 		// Get the captured output
 		output := buf.String()
@@ -799,7 +1378,7 @@ func (m *Model) submitTerminalCommand() tea.Cmd {
 func (m *Model) executeCommand(cmdLine string) tea.Cmd {
 	return func() tea.Msg {
 		// Parse command line
-		args := parseCommandLine(cmdLine)
+		args := shell.ParseCommandLine(cmdLine)
 		if len(args) == 0 {
 			return commandExecutedMsg{output: "", error: ""}
 		}
@@ -810,7 +1389,7 @@ func (m *Model) executeCommand(cmdLine string) tea.Cmd {
 		// Create a buffer to capture command output
 		var buf strings.Builder
 
-		exitCode, err := m.adapter.vfs.Execute(m.adapter.ctx, &buf, args...)
+		exitCode, err := m.adapter.Execute(&buf, args...)
 
 		// Get the captured output
 		output = buf.String()
@@ -829,43 +1408,3 @@ func (m *Model) executeCommand(cmdLine string) tea.Cmd {
 		}
 	}
 }
-
-// parseCommandLine splits a command line into tokens (same as original main.go)
-func parseCommandLine(line string) []string {
-	var args []string
-	var current strings.Builder
-	inQuote := false
-	quoteChar := rune(0)
-
-	for _, ch := range line {
-		switch {
-		case ch == '"' || ch == '\'':
-			if inQuote {
-				if ch == quoteChar {
-					inQuote = false
-					quoteChar = 0
-				} else {
-					current.WriteRune(ch)
-				}
-			} else {
-				inQuote = true
-				quoteChar = ch
-			}
-
-		case ch == ' ' && !inQuote:
-			if current.Len() > 0 {
-				args = append(args, current.String())
-				current.Reset()
-			}
-
-		default:
-			current.WriteRune(ch)
-		}
-	}
-
-	if current.Len() > 0 {
-		args = append(args, current.String())
-	}
-
-	return args
-}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"path/filepath"
+
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// maxHistory limits how many visited directories each pane remembers
+const maxHistory = 100
+
+// Pane holds the navigation and selection state of a single file list.
+// The model keeps two panes; only the active one is shown unless dual-pane mode is enabled.
+type Pane struct {
+	// Navigation state
+	currentPath string
+	previousDir string              // Name of directory we came from (for breadcrumb navigation)
+	allEntries  []*vfsadapter.Entry // Full directory listing
+	entries     []*vfsadapter.Entry // Listing narrowed by the filter
+	cursor      int
+	offset      int
+
+	// Filter state
+	filter string // Pattern narrowing the listing, empty if inactive
+
+	// Tree state
+	tree *treeState // Expanded directories, nil while shown as flat list
+
+	// Selection state
+	marked       map[string]bool // Paths of marked entries
+	visualAnchor int             // Start of range selection, -1 if inactive
+
+	// History state
+	backStack    []string // Previously visited directories, most recent last
+	forwardStack []string // Directories left via history back, most recent last
+}
+
+// NewPane creates a pane that starts at path
+func NewPane(path string) *Pane {
+	return &Pane{
+		currentPath:  path,
+		marked:       make(map[string]bool),
+		visualAnchor: -1,
+	}
+}
+
+// setEntries replaces the listing and restores the cursor position
+func (p *Pane) setEntries(entries []*vfsadapter.Entry, visibleLines int) {
+	p.allEntries = entries
+	if err := p.applyFilter(); err != nil {
+		p.entries = p.allEntries
+	}
+	p.pruneSelection()
+
+	// Position cursor on previous directory if we just navigated back
+	if p.previousDir != "" {
+		for i, entry := range p.entries {
+			if entry.Name == p.previousDir {
+				p.cursor = i
+				// Adjust offset to keep cursor visible
+				if p.cursor >= p.offset+visibleLines {
+					p.offset = p.cursor - visibleLines + 1
+				} else if p.cursor < p.offset {
+					p.offset = p.cursor
+				}
+				break
+			}
+		}
+		p.previousDir = "" // Clear after using
+		return
+	}
+
+	// Normal navigation - position at top
+	if len(p.entries) > 0 && p.cursor >= len(p.entries) {
+		p.cursor = len(p.entries) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+// moveCursor moves the cursor by delta, handling bounds and scrolling
+func (p *Pane) moveCursor(delta, visibleLines int) {
+	if len(p.entries) == 0 {
+		return
+	}
+
+	p.cursor += delta
+
+	// Clamp cursor
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	if p.cursor >= len(p.entries) {
+		p.cursor = len(p.entries) - 1
+	}
+
+	// Adjust offset for scrolling
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visibleLines {
+		p.offset = p.cursor - visibleLines + 1
+	}
+}
+
+// currentEntry returns the entry under the cursor
+func (p *Pane) currentEntry() *vfsadapter.Entry {
+	if p.cursor >= 0 && p.cursor < len(p.entries) {
+		return p.entries[p.cursor]
+	}
+	return nil
+}
+
+// changeDirectory switches the pane to path and records the visit in the history
+func (p *Pane) changeDirectory(path string) {
+	if path != p.currentPath {
+		p.backStack = appendHistory(p.backStack, p.currentPath)
+		p.forwardStack = nil
+	}
+	p.setDirectory(path)
+}
+
+// setDirectory switches the pane to path and resets cursor and selection
+func (p *Pane) setDirectory(path string) {
+	p.currentPath = path
+	p.previousDir = "" // Clear previous directory when entering new one
+	p.filter = ""
+	p.clearSelection()
+	if p.tree != nil {
+		p.tree = newTreeState()
+	}
+	p.cursor = 0
+	p.offset = 0
+}
+
+// historyBack returns to the previously visited directory
+func (p *Pane) historyBack() bool {
+	if len(p.backStack) == 0 {
+		return false
+	}
+
+	path := p.backStack[len(p.backStack)-1]
+	p.backStack = p.backStack[:len(p.backStack)-1]
+	p.forwardStack = appendHistory(p.forwardStack, p.currentPath)
+	p.setDirectory(path)
+	return true
+}
+
+// historyForward revisits the directory left by the last historyBack
+func (p *Pane) historyForward() bool {
+	if len(p.forwardStack) == 0 {
+		return false
+	}
+
+	path := p.forwardStack[len(p.forwardStack)-1]
+	p.forwardStack = p.forwardStack[:len(p.forwardStack)-1]
+	p.backStack = appendHistory(p.backStack, p.currentPath)
+	p.setDirectory(path)
+	return true
+}
+
+// jumpList returns the whole history from oldest to newest
+// together with the index of the current directory
+func (p *Pane) jumpList() ([]string, int) {
+	list := make([]string, 0, len(p.backStack)+len(p.forwardStack)+1)
+	list = append(list, p.backStack...)
+	list = append(list, p.currentPath)
+	for i := len(p.forwardStack) - 1; i >= 0; i-- {
+		list = append(list, p.forwardStack[i])
+	}
+	return list, len(p.backStack)
+}
+
+// jumpTo moves through the history until the entry at index of the jump list is current
+func (p *Pane) jumpTo(index int) {
+	for len(p.backStack) > index {
+		p.historyBack()
+	}
+	for len(p.backStack) < index && len(p.forwardStack) > 0 {
+		p.historyForward()
+	}
+}
+
+// appendHistory appends path to stack, dropping the oldest entries beyond maxHistory
+func appendHistory(stack []string, path string) []string {
+	stack = append(stack, path)
+	if len(stack) > maxHistory {
+		stack = stack[len(stack)-maxHistory:]
+	}
+	return stack
+}
+
+// parentDirectory switches the pane to the parent of its current directory
+// and remembers the directory it came from so the cursor can be placed on it
+func (p *Pane) parentDirectory() bool {
+	if p.currentPath == "/" {
+		return false
+	}
+
+	previous := filepath.Base(p.currentPath)
+	p.changeDirectory(filepath.Dir(p.currentPath))
+	p.previousDir = previous
+	return true
+}
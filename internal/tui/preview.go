@@ -12,145 +12,30 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/eliukblau/pixterm/pkg/ansimage"
-	"github.com/mwantia/vfs/data"
-	"golang.org/x/image/draw"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
 )
 
-// PreviewType represents how a file should be previewed
-type PreviewType int
-
-const (
-	PreviewText PreviewType = iota
-	PreviewImage
-	PreviewBinary
-	PreviewUnsupported
-)
-
-// FileTypeInfo contains information about how to preview a file
-type FileTypeInfo struct {
-	Type        PreviewType
-	Description string
-}
-
-// DetectFileType determines the appropriate preview type for a file
-func DetectFileType(filename string) FileTypeInfo {
-	ext := strings.ToLower(filepath.Ext(filename))
-
-	// Image files
-	imageExts := map[string]bool{}
-	if imageExts[ext] {
-		return FileTypeInfo{
-			Type:        PreviewImage,
-			Description: "Image file",
-		}
-	}
-
-	// Text files
-	textExts := map[string]bool{
-		".txt": true, ".md": true, ".go": true, ".js": true,
-		".py": true, ".java": true, ".c": true, ".cpp": true,
-		".h": true, ".hpp": true, ".rs": true, ".sh": true,
-		".bash": true, ".zsh": true, ".fish": true,
-		".json": true, ".xml": true, ".yaml": true, ".yml": true,
-		".toml": true, ".ini": true, ".cfg": true, ".conf": true,
-		".html": true, ".css": true, ".scss": true, ".sass": true,
-		".sql": true, ".log": true, ".csv": true, ".tsv": true,
-		".gitignore": true, ".dockerfile": true, ".env": true,
-	}
-	if textExts[ext] {
-		return FileTypeInfo{
-			Type:        PreviewText,
-			Description: "Text file",
-		}
-	}
-
-	// Binary files that shouldn't be previewed as text
-	binaryExts := map[string]bool{
-		".zip": true, ".gz": true, ".tar": true, ".bz2": true,
-		".7z": true, ".rar": true, ".xz": true,
-		".exe": true, ".dll": true, ".so": true, ".dylib": true,
-		".bin": true, ".dat": true, ".db": true, ".sqlite": true,
-		".pdf": true, ".doc": true, ".docx": true, ".xls": true,
-		".xlsx": true, ".ppt": true, ".pptx": true,
-		".mp3": true, ".mp4": true, ".avi": true, ".mkv": true,
-		".wav": true, ".flac": true, ".ogg": true,
-
-		".png": true, ".jpg": true, ".jpeg": true,
-		".gif": true, ".bmp": true, ".webp": true,
-	}
-	if binaryExts[ext] {
-		return FileTypeInfo{
-			Type:        PreviewBinary,
-			Description: "Binary file",
-		}
-	}
-
-	// Files without extension or unknown - try to detect
-	if ext == "" || ext == filename {
-		return FileTypeInfo{
-			Type:        PreviewText, // Try text, will be validated
-			Description: "Unknown type",
-		}
-	}
-
-	// Default to text for unknown extensions
-	return FileTypeInfo{
-		Type:        PreviewText,
-		Description: "Unknown text file",
-	}
-}
-
-// isValidUTF8 checks if data appears to be valid UTF-8 text
-func isValidUTF8(data []byte) bool {
-	// Check if it's valid UTF-8
-	if !utf8.Valid(data) {
-		return false
-	}
-
-	// Check for control characters (except common ones)
-	controlCharCount := 0
-	for _, b := range data {
-		// Allow: tab (9), newline (10), carriage return (13)
-		if b < 32 && b != 9 && b != 10 && b != 13 {
-			controlCharCount++
-		}
-	}
-
-	// If more than 5% control characters, likely binary
-	return float64(controlCharCount)/float64(len(data)) < 0.05
-}
-
 // GenerateTextPreview creates a text preview of a file
-func (a *VFSAdapter) GenerateTextPreview(path string, maxBytes int) (string, error) {
-	file, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeRead)
+func GenerateTextPreview(a *vfsadapter.Adapter, path string, maxBytes int) (string, error) {
+	text, ok, err := a.ReadText(path, maxBytes)
 	if err != nil {
 		return "", err
 	}
-	defer file.Close()
-
-	// Read up to maxBytes
-	buf := make([]byte, maxBytes)
-	n, err := io.ReadFull(file, buf)
-	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-		return "", err
-	}
-	buf = buf[:n]
-
-	// Validate it's actually text
-	if !isValidUTF8(buf) {
+	if !ok {
 		return "[Binary file - cannot preview as text]", nil
 	}
-
-	return string(buf), nil
+	return text, nil
 }
 
-// GenerateImagePreview creates an ANSI art preview of an image
-func (a *VFSAdapter) GenerateImagePreview(path string, previewWidth, previewHeight int) (string, error) {
+// GenerateImagePreview renders an image into previewWidth x previewHeight cells,
+// using a pixel protocol if the terminal supports one and ANSI half-blocks otherwise
+func GenerateImagePreview(a *vfsadapter.Adapter, path string, previewWidth, previewHeight int, protocol GraphicsProtocol) (string, error) {
 	// First check file size to prevent loading huge images
-	stat, err := a.vfs.StatMetadata(a.ctx, path)
+	stat, err := a.Stat(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to stat image: %w", err)
 	}
@@ -162,7 +47,7 @@ func (a *VFSAdapter) GenerateImagePreview(path string, previewWidth, previewHeig
 			float64(stat.Size)/(1024*1024)), nil
 	}
 
-	file, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeRead)
+	file, err := a.StreamFile(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to open image: %w", err)
 	}
@@ -174,22 +59,32 @@ func (a *VFSAdapter) GenerateImagePreview(path string, previewWidth, previewHeig
 		return "", fmt.Errorf("failed to decode image: %w", err)
 	}
 
-	maxHeight := 80
-	maxWidth := 260
-
 	bounds := img.Bounds()
 	imgWidth := bounds.Dx()
 	imgHeight := bounds.Dy()
 
-	scale := min64(float64(maxWidth)/float64(imgWidth), float64(maxHeight)/float64(imgHeight))
-	if scale > 1.0 {
-		scale = 1.0 // don’t upscale
+	header := fmt.Sprintf("Image: %s format, %dx%d pixels\n\n", format, imgWidth, imgHeight)
+
+	// Leave room for the header within the preview
+	cols, rows := fitCells(imgWidth, imgHeight, previewWidth, previewHeight-2)
+	if cols == 0 || rows == 0 {
+		return header, nil
+	}
+
+	if protocol != GraphicsANSI {
+		rendered, err := encodeGraphics(img, protocol, cols, rows)
+		if err != nil {
+			return "", err
+		}
+		return header + rendered, nil
 	}
-	newW := int(float64(imgWidth) * float64(scale))
-	newH := int(float64(imgHeight) * float64(scale))
 
-	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
-	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	// Half-blocks show two pixels per cell, one above the other, and are never upscaled
+	newW, newH := imgWidth, imgHeight
+	if cols < imgWidth {
+		newW, newH = cols, rows*2
+	}
+	dst := scaleImage(img, newW, newH)
 
 	// Create ANSI image with calculated dimensions
 	ansImg, err := ansimage.NewFromImage(dst, color.Transparent, ansimage.NoDithering)
@@ -197,22 +92,19 @@ func (a *VFSAdapter) GenerateImagePreview(path string, previewWidth, previewHeig
 		return "", fmt.Errorf("failed to create ANSI image: %w", err)
 	}
 
-	rendered := ansImg.Render()
-	header := fmt.Sprintf("Image: %s format, %dx%d pixels\n\n", format, imgWidth, imgHeight)
-
-	return header + rendered, nil
+	return header + ansImg.Render(), nil
 }
 
 // GenerateBinaryPreview creates a hex dump preview of a binary file
-func (a *VFSAdapter) GenerateBinaryPreview(path string, maxBytes int) (string, error) {
-	file, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeRead)
+func GenerateBinaryPreview(a *vfsadapter.Adapter, path string, maxBytes int) (string, error) {
+	file, err := a.StreamFile(path)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
 	// Get file info
-	stat, err := a.vfs.StatMetadata(a.ctx, path)
+	stat, err := a.Stat(path)
 	if err != nil {
 		return "", err
 	}
@@ -245,32 +137,89 @@ func (a *VFSAdapter) GenerateBinaryPreview(path string, maxBytes int) (string, e
 	return preview.String(), nil
 }
 
-// GeneratePreview generates an appropriate preview for any file
-func (a *VFSAdapter) GeneratePreview(path string, previewWidth, previewHeight int) (string, error) {
-	fileInfo := DetectFileType(path)
+// generateTextPreview reads the first 10KB of a text file and highlights them
+func generateTextPreview(a *vfsadapter.Adapter, entry *vfsadapter.Entry, fileType vfsadapter.FileType, opts PreviewOptions) (string, error) {
+	limit := 10240 // 10KB
+	if opts.TextLimit > 0 {
+		limit = opts.TextLimit
+	}
+
+	text, ok, err := a.ReadText(entry.Path, limit)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "[Binary file - cannot preview as text]", nil
+	}
+	if opts.Theme == nil || text == "" {
+		return text, nil
+	}
+	return highlightText(text, detectLanguage(fileType, entry.Name, text), opts.Theme, opts.Width), nil
+}
+
+// PreviewOptions describes the space and styling available to a preview
+type PreviewOptions struct {
+	Width    int // Cells per line
+	Height   int // Lines
+	Graphics GraphicsProtocol
+	Theme    *Theme // Highlights text if set
+	Depth    int    // Levels of nested data shown expanded
+	Raw      bool   // Show the source of Markdown and data files instead of rendering them
+
+	// TextLimit is how many bytes of a text file are shown, 10KB if zero
+	TextLimit int
+}
+
+// GeneratePreview generates an appropriate preview for any file,
+// fitting it into the cells given by opts
+func GeneratePreview(a *vfsadapter.Adapter, path string, opts PreviewOptions) (string, error) {
+	entry, err := a.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	fileType := a.SniffFileType(entry)
+
+	switch fileType.Preview() {
+	case vfsadapter.PreviewText:
+		return generateTextPreview(a, entry, fileType, opts)
+
+	case vfsadapter.PreviewJSON, vfsadapter.PreviewYAML, vfsadapter.PreviewCSV, vfsadapter.PreviewTOML, vfsadapter.PreviewINI:
+		if opts.Theme == nil || opts.Raw {
+			return generateTextPreview(a, entry, fileType, opts)
+		}
+		return generateStructuredPreview(a, entry, fileType, fileType.Preview(), opts)
 
-	switch fileInfo.Type {
-	case PreviewText:
-		content, err := a.GenerateTextPreview(path, 10240) // 10KB
+	case vfsadapter.PreviewMarkdown:
+		if opts.Theme == nil || opts.Raw {
+			return generateTextPreview(a, entry, fileType, opts)
+		}
+		return generateMarkdownPreview(a, entry, opts)
+
+	case vfsadapter.PreviewArchive:
+		// Archives inside archives are not opened, and unreadable ones are shown as binary
+		if entry.InArchive() {
+			return GenerateBinaryPreview(a, path, 1024)
+		}
+		content, err := a.DescribeArchive(entry.Path, opts.Width, opts.Height)
 		if err != nil {
-			return "", err
+			return GenerateBinaryPreview(a, path, 1024)
 		}
 		return content, nil
 
-	case PreviewImage:
+	case vfsadapter.PreviewImage:
 		// Reserve space for header and borders
-		content, err := a.GenerateImagePreview(path, previewWidth, previewHeight)
+		content, err := GenerateImagePreview(a, path, opts.Width, opts.Height, opts.Graphics)
 		if err != nil {
 			// If image rendering fails, fall back to binary preview
-			return a.GenerateBinaryPreview(path, 1024)
+			return GenerateBinaryPreview(a, path, 1024)
 		}
 		return content, nil
 
-	case PreviewBinary:
-		return a.GenerateBinaryPreview(path, 1024) // 1KB hex dump
+	case vfsadapter.PreviewBinary:
+		return GenerateBinaryPreview(a, path, 1024) // 1KB hex dump
 
-	case PreviewUnsupported:
-		return fmt.Sprintf("[Cannot preview %s files]", fileInfo.Description), nil
+	case vfsadapter.PreviewUnsupported:
+		return fmt.Sprintf("[Cannot preview: %s]", fileType.Kind), nil
 
 	default:
 		return "[Unknown file type]", nil
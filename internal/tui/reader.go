@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// Reader shows a single file full-screen, with Markdown and data files rendered
+type Reader struct {
+	Path string
+
+	lines  []string
+	offset int  // First line shown
+	raw    bool // Show the source instead of the rendered file
+	loaded bool
+	err    error
+}
+
+// readerLoadedMsg delivers the rendered content of the file shown in the reader
+type readerLoadedMsg struct {
+	reader  *Reader
+	content string
+	err     error
+}
+
+// openReader shows entry in the full-screen reader
+func (m *Model) openReader(entry *vfsadapter.Entry) tea.Cmd {
+	m.reader = &Reader{Path: entry.Path, raw: m.tab().rawPreview}
+	m.mode = ModeReader
+	return m.loadReader()
+}
+
+// loadReader renders the file of the reader for the current window size
+func (m *Model) loadReader() tea.Cmd {
+	reader := m.reader
+	opts := PreviewOptions{
+		Width:     m.width - 4,
+		Graphics:  GraphicsANSI,
+		Theme:     m.theme,
+		Depth:     m.tab().previewDepth,
+		Raw:       reader.raw,
+		TextLimit: structuredPreviewSize,
+	}
+
+	return func() tea.Msg {
+		// Images need a height to be scaled to, everything else is shown in full
+		if entry, err := m.adapter.Stat(reader.Path); err == nil && m.adapter.SniffFileType(entry).Preview() == vfsadapter.PreviewImage {
+			opts.Height = m.readerHeight()
+		}
+
+		content, err := GeneratePreview(m.adapter, reader.Path, opts)
+		return readerLoadedMsg{reader: reader, content: content, err: err}
+	}
+}
+
+// readerHeight returns how many lines of the file fit on screen
+func (m *Model) readerHeight() int {
+	return max(m.height-6, 1) // Reserve for title, help, padding
+}
+
+// scrollReader moves the reader by delta lines, keeping the last page full
+func (m *Model) scrollReader(delta int) {
+	last := max(len(m.reader.lines)-m.readerHeight(), 0)
+	m.reader.offset = min(max(m.reader.offset+delta, 0), last)
+}
+
+// handleReaderMode processes keys in the full-screen reader
+func (m *Model) handleReaderMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.readerHeight()
+
+	switch {
+	case key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Back), msg.Type == tea.KeyEscape:
+		m.mode = ModeNormal
+		m.reader = nil
+
+	case key.Matches(msg, m.keys.Up):
+		m.scrollReader(-1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.scrollReader(1)
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.scrollReader(-page)
+
+	case key.Matches(msg, m.keys.PageDown), msg.String() == " ":
+		m.scrollReader(page)
+
+	case key.Matches(msg, m.keys.Top):
+		m.reader.offset = 0
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.scrollReader(len(m.reader.lines))
+
+	case key.Matches(msg, m.keys.RawPreview):
+		m.reader.raw = !m.reader.raw
+		return m, m.loadReader()
+
+	case key.Matches(msg, m.keys.PreviewExpand):
+		return m, tea.Batch(m.changePreviewDepth(1), m.loadReader())
+
+	case key.Matches(msg, m.keys.PreviewCollapse):
+		return m, tea.Batch(m.changePreviewDepth(-1), m.loadReader())
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.loadReader()
+	}
+
+	return m, nil
+}
+
+// renderReaderView renders the file of the reader full-screen
+func (m *Model) renderReaderView() string {
+	var sections []string
+
+	reader := m.reader
+	sections = append(sections, m.theme.TitleStyle.Render(
+		fmt.Sprintf("VFS Reader - %s - Press esc to return to Navigation", reader.Path)))
+
+	availableHeight := m.readerHeight()
+
+	var content string
+	switch {
+	case reader.err != nil:
+		content = m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", reader.err))
+	case !reader.loaded:
+		content = m.theme.NormalItemStyle.Render("Loading...")
+	case len(reader.lines) == 0:
+		content = m.theme.NormalItemStyle.Render("(empty file)")
+	default:
+		end := min(reader.offset+availableHeight, len(reader.lines))
+		content = strings.Join(reader.lines[reader.offset:end], "\n")
+	}
+
+	sections = append(sections, m.theme.BorderStyle.
+		Width(m.width-4).
+		Height(availableHeight).
+		Render(content))
+
+	mode := "rendered"
+	if reader.raw {
+		mode = "raw"
+	}
+	position := fmt.Sprintf("lines %d-%d of %d (%s)", min(reader.offset+1, len(reader.lines)),
+		min(reader.offset+availableHeight, len(reader.lines)), len(reader.lines), mode)
+	sections = append(sections, m.theme.HelpStyle.Render(
+		position+" • ↑/↓ scroll • pgup/pgdn page • R raw/rendered • +/- depth • esc back"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
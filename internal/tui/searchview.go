@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// maxSearchResults limits how many matches a search from the TUI collects
+const maxSearchResults = 1000
+
+// SearchResults collects the matches of the most recent content search.
+// Matches are added by the search job while the view may already show them.
+type SearchResults struct {
+	Query string
+	Root  string
+
+	mu      sync.Mutex
+	matches []vfsadapter.SearchMatch
+}
+
+// add appends a match found by the search job
+func (r *SearchResults) add(match vfsadapter.SearchMatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matches = append(r.matches, match)
+}
+
+// Matches returns a snapshot of all matches found so far
+func (r *SearchResults) Matches() []vfsadapter.SearchMatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]vfsadapter.SearchMatch(nil), r.matches...)
+}
+
+// startSearch searches the current directory tree as background job.
+// Patterns starting with "re:" are regular expressions, and
+// patterns without uppercase letters are matched case-insensitively.
+func (m *Model) startSearch(query string) tea.Cmd {
+	opts := vfsadapter.SearchOptions{
+		Pattern:    query,
+		IgnoreCase: strings.ToLower(query) == query,
+		MaxResults: maxSearchResults,
+	}
+	if expr, ok := strings.CutPrefix(query, regexPrefix); ok {
+		opts.Pattern = expr
+		opts.Regex = true
+		opts.IgnoreCase = strings.ToLower(expr) == expr
+	}
+
+	// Report invalid patterns right away instead of as failed job
+	if err := opts.Validate(); err != nil {
+		m.errorMsg = err.Error()
+		return nil
+	}
+
+	root := m.pane().currentPath
+	results := &SearchResults{Query: query, Root: root}
+	m.search = results
+	m.searchCursor = 0
+
+	job := m.startJob(fmt.Sprintf("Search '%s'", query), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		summary, err := adapter.Search(root, opts, report, results.add)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Found %d match(es) in %d file(s), skipped %d", summary.Matches, summary.Files, summary.Skipped), nil
+	})
+	m.searchJobID = job.ID
+
+	return nil
+}
+
+// handleSearchMode processes keys in the search results view
+func (m *Model) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var matches []vfsadapter.SearchMatch
+	if m.search != nil {
+		matches = m.search.Matches()
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.SearchResults), key.Matches(msg, m.keys.Quit), msg.Type == tea.KeyEscape:
+		m.mode = ModeNormal
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		m.searchCursor = max(m.searchCursor-1, 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Down):
+		m.searchCursor = max(min(m.searchCursor+1, len(matches)-1), 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.searchCursor = max(m.searchCursor-10, 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.PageDown):
+		m.searchCursor = max(min(m.searchCursor+10, len(matches)-1), 0)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.searchCursor >= len(matches) {
+			return m, nil
+		}
+		match := matches[m.searchCursor]
+
+		// Open the file's directory with the cursor on the file and its preview shown
+		p := m.pane()
+		p.changeDirectory(filepath.Dir(match.Path))
+		p.previousDir = filepath.Base(match.Path)
+		m.tab().showPreview = true
+		m.mode = ModeNormal
+		m.statusMsg = fmt.Sprintf("Match at line %d", match.Line)
+		return m, m.loadDirectory()
+	}
+
+	return m, nil
+}
+
+// renderSearchView renders the full-screen list of search results
+func (m *Model) renderSearchView() string {
+	var sections []string
+
+	header := "VFS Search - Press S to return to Navigation"
+	if m.search != nil {
+		header = fmt.Sprintf("VFS Search - '%s' in %s - Press S to return to Navigation", m.search.Query, m.search.Root)
+	}
+	sections = append(sections, m.theme.TitleStyle.Render(header))
+
+	var matches []vfsadapter.SearchMatch
+	if m.search != nil {
+		matches = m.search.Matches()
+	}
+	availableHeight := m.height - 6 // Reserve for title, help, padding
+
+	var lines []string
+	if len(matches) == 0 {
+		if job := m.jobs.Get(m.searchJobID); job != nil && job.State == JobRunning {
+			lines = append(lines, m.theme.NormalItemStyle.Render("(searching...)"))
+		} else {
+			lines = append(lines, m.theme.NormalItemStyle.Render("(no matches)"))
+		}
+	}
+
+	// Keep the selected match in view
+	start := 0
+	if m.searchCursor >= availableHeight {
+		start = m.searchCursor - availableHeight + 1
+	}
+	end := min(start+availableHeight, len(matches))
+
+	for i := start; i < end; i++ {
+		match := matches[i]
+		location := fmt.Sprintf("%s:%d:", match.Path, match.Line)
+
+		// Cut long lines instead of letting them wrap
+		text := truncate(match.Text, m.width-8-len(location))
+
+		if i == m.searchCursor {
+			lines = append(lines, m.theme.SelectedItemStyle.Render(location+" "+text))
+		} else {
+			lines = append(lines, m.theme.DirectoryStyle.Render(location)+" "+m.theme.NormalItemStyle.Render(text))
+		}
+	}
+
+	sections = append(sections, m.theme.BorderStyle.
+		Width(m.width-4).
+		Height(availableHeight).
+		Render(strings.Join(lines, "\n")))
+
+	sections = append(sections, m.theme.HelpStyle.Render("↑/↓ select • enter open in preview • S/esc back"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// truncate shortens s to at most width cells, marking the cut with "...".
+// Wide characters such as CJK take up two cells.
+func truncate(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, "...")
+}
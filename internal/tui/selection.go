@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// isMarked reports whether the entry at index i is part of the selection,
+// including the pending range while visual mode is active
+func (p *Pane) isMarked(i int) bool {
+	if i < 0 || i >= len(p.entries) {
+		return false
+	}
+	if p.marked[p.entries[i].Path] {
+		return true
+	}
+	return p.inVisualRange(i)
+}
+
+// inVisualRange reports whether index i lies between the visual anchor and the cursor
+func (p *Pane) inVisualRange(i int) bool {
+	if p.visualAnchor < 0 {
+		return false
+	}
+
+	start, end := p.visualAnchor, p.cursor
+	if start > end {
+		start, end = end, start
+	}
+	return i >= start && i <= end
+}
+
+// toggleMark adds or removes the current entry from the selection
+func (p *Pane) toggleMark() {
+	entry := p.currentEntry()
+	if entry == nil {
+		return
+	}
+
+	if p.marked[entry.Path] {
+		delete(p.marked, entry.Path)
+	} else {
+		p.marked[entry.Path] = true
+	}
+}
+
+// toggleVisual starts range selection at the cursor or commits the current range
+func (p *Pane) toggleVisual() {
+	if p.visualAnchor < 0 {
+		if len(p.entries) > 0 {
+			p.visualAnchor = p.cursor
+		}
+		return
+	}
+
+	p.commitVisual()
+}
+
+// commitVisual adds the pending visual range to the selection and leaves visual mode
+func (p *Pane) commitVisual() {
+	if p.visualAnchor < 0 {
+		return
+	}
+
+	for i := range p.entries {
+		if p.inVisualRange(i) {
+			p.marked[p.entries[i].Path] = true
+		}
+	}
+	p.visualAnchor = -1
+}
+
+// selectAll marks every entry in the current directory
+func (p *Pane) selectAll() {
+	p.visualAnchor = -1
+	for _, entry := range p.entries {
+		p.marked[entry.Path] = true
+	}
+}
+
+// invertSelection marks every unmarked entry and unmarks every marked entry
+func (p *Pane) invertSelection() {
+	p.commitVisual()
+	for _, entry := range p.entries {
+		if p.marked[entry.Path] {
+			delete(p.marked, entry.Path)
+		} else {
+			p.marked[entry.Path] = true
+		}
+	}
+}
+
+// clearSelection removes all marks and leaves visual mode
+func (p *Pane) clearSelection() {
+	p.marked = make(map[string]bool)
+	p.visualAnchor = -1
+}
+
+// hasSelection reports whether any entries are marked
+func (p *Pane) hasSelection() bool {
+	return len(p.marked) > 0 || p.visualAnchor >= 0
+}
+
+// pruneSelection drops marks for entries that no longer exist in the listing
+func (p *Pane) pruneSelection() {
+	existing := make(map[string]bool, len(p.allEntries))
+	for _, entry := range p.allEntries {
+		existing[entry.Path] = true
+	}
+
+	for path := range p.marked {
+		if !existing[path] {
+			delete(p.marked, path)
+		}
+	}
+}
+
+// markedCount returns how many marked entries are visible and would be acted on,
+// and how many are hidden by the filter
+func (p *Pane) markedCount() (visible, hidden int) {
+	for _, entry := range p.entries {
+		if p.marked[entry.Path] {
+			visible++
+		}
+	}
+	return visible, len(p.marked) - visible
+}
+
+// selectedEntries returns the visible marked entries in listing order,
+// or the entry under the cursor if none of the marks are visible
+func (p *Pane) selectedEntries() []*vfsadapter.Entry {
+	p.commitVisual()
+
+	entries := make([]*vfsadapter.Entry, 0, len(p.marked))
+	for _, entry := range p.entries {
+		if p.marked[entry.Path] {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > 0 {
+		return entries
+	}
+
+	// Marks hidden by the filter are never acted on, the status line shows them instead
+	if entry := p.currentEntry(); entry != nil {
+		return []*vfsadapter.Entry{entry}
+	}
+	return nil
+}
+
+// describeSelection summarizes entries for confirmation prompts. The size only
+// covers files, as the contents of directories are not known without a walk.
+func describeSelection(entries []*vfsadapter.Entry) string {
+	if len(entries) == 1 {
+		return entries[0].Name
+	}
+
+	var files, dirs int
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir {
+			dirs++
+			continue
+		}
+		files++
+		total += entry.Size
+	}
+
+	switch {
+	case dirs == 0:
+		return fmt.Sprintf("%d files (%s)", files, vfsadapter.FormatSize(total))
+	case files == 0:
+		return fmt.Sprintf("%d directories", dirs)
+	default:
+		return fmt.Sprintf("%d files (%s) and %d directories", files, vfsadapter.FormatSize(total), dirs)
+	}
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/config"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// sortEntries orders entries in place according to settings.
+// Ties are broken by natural name order so listings are stable across reloads.
+func sortEntries(entries []*vfsadapter.Entry, settings config.SortSettings) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if settings.DirsFirst && a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+
+		cmp := compareEntries(a, b, settings.By)
+		if cmp == 0 {
+			cmp = naturalCompare(a.Name, b.Name)
+		}
+		if settings.Descending {
+			cmp = -cmp
+		}
+		return cmp < 0
+	})
+}
+
+// compareEntries compares two entries by a single sort mode
+func compareEntries(a, b *vfsadapter.Entry, by string) int {
+	switch by {
+	case config.SortBySize:
+		return compareInt64(a.Size, b.Size)
+	case config.SortByModTime:
+		return a.ModTime.Compare(b.ModTime)
+	case config.SortByType:
+		return strings.Compare(a.FileType().MimeType, b.FileType().MimeType)
+	case config.SortByExtension:
+		return strings.Compare(strings.ToLower(filepath.Ext(a.Name)), strings.ToLower(filepath.Ext(b.Name)))
+	default:
+		return naturalCompare(a.Name, b.Name)
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// naturalCompare compares names case-insensitively while ordering
+// embedded numbers by value, so "file2" sorts before "file10"
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	for len(a) > 0 && len(b) > 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			numA, restA := splitDigits(a)
+			numB, restB := splitDigits(b)
+
+			// Compare by value without parsing, so long numbers cannot overflow
+			trimA, trimB := strings.TrimLeft(numA, "0"), strings.TrimLeft(numB, "0")
+			if len(trimA) != len(trimB) {
+				return compareInt64(int64(len(trimA)), int64(len(trimB)))
+			}
+			if cmp := strings.Compare(trimA, trimB); cmp != 0 {
+				return cmp
+			}
+
+			a, b = restA, restB
+			continue
+		}
+
+		if a[0] != b[0] {
+			return compareInt64(int64(a[0]), int64(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return compareInt64(int64(len(a)), int64(len(b)))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitDigits splits s into its leading run of digits and the rest
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// describeSort returns a short label of the settings for the status bar
+func describeSort(settings config.SortSettings) string {
+	label := settings.By + " ↑"
+	if settings.Descending {
+		label = settings.By + " ↓"
+	}
+	if settings.DirsFirst {
+		label += " dirs first"
+	}
+	return label
+}
+
+// sortSettings returns the order used for the listing of dir
+func (m *Model) sortSettings(dir string) config.SortSettings {
+	return m.sorting.For(dir)
+}
+
+// changeSort applies fn to the order of the current directory, stores the
+// result and re-sorts every visible pane without reloading it
+func (m *Model) changeSort(fn func(config.SortSettings) config.SortSettings) tea.Cmd {
+	dir := m.pane().currentPath
+	m.sorting.Set(dir, fn(m.sortSettings(dir)))
+
+	if m.sortPath != "" {
+		if err := config.SaveSortConfig(m.sortPath, m.sorting); err != nil {
+			m.errorMsg = err.Error()
+		}
+	}
+
+	panes := []*Pane{m.pane()}
+	if m.tab().dualPane {
+		panes = append(panes, m.otherPane())
+	}
+	for _, p := range panes {
+		p.resort(m.sortSettings(p.currentPath), m.getPaneLines())
+	}
+
+	m.statusMsg = fmt.Sprintf("Sort: %s", describeSort(m.sortSettings(dir)))
+	return m.updatePreview()
+}
+
+// resort orders the listing again and keeps the cursor on the same entry
+func (p *Pane) resort(settings config.SortSettings, visibleLines int) {
+	current := p.currentEntry()
+	p.commitVisual()
+
+	if p.tree != nil {
+		for _, children := range p.tree.children {
+			sortEntries(children, settings)
+		}
+		p.allEntries = p.tree.flatten(p.currentPath)
+	} else {
+		sortEntries(p.allEntries, settings)
+	}
+	if err := p.applyFilter(); err != nil {
+		p.entries = p.allEntries
+	}
+	p.focusEntry(current, visibleLines)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import "testing"
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"file10", "file10", 0},
+		{"File", "file", 0},
+		{"a", "B", -1},
+		{"file01", "file1", 0},
+		{"file1a", "file1b", -1},
+		{"file", "file1", -1},
+		{"2", "10", -1},
+		{"1.9", "1.10", -1},
+		{"abc", "ab", 1},
+		{"", "", 0},
+		{"", "a", -1},
+		{"x99999999999999999999999", "x100000000000000000000000", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := naturalCompare(tt.a, tt.b); got != tt.want {
+				t.Fatalf("naturalCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,615 @@
+package tui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// structuredPreviewSize is the largest file that is parsed for a structured preview.
+	// Larger files are shown as highlighted text instead.
+	structuredPreviewSize = 256 * 1024
+
+	// defaultPreviewDepth is how many levels of nested data are expanded initially
+	defaultPreviewDepth = 3
+	// maxPreviewDepth limits how far nested data can be expanded
+	maxPreviewDepth = 16
+
+	// csvMaxColumnWidth is the widest a table column gets before its values are cut
+	csvMaxColumnWidth = 24
+)
+
+// parseError is the position and reason of a syntax error in a data file
+type parseError struct {
+	line   int // 1-based
+	column int // 1-based, in characters; 0 if unknown
+	msg    string
+}
+
+// Error implements the error interface
+func (e *parseError) Error() string {
+	if e.column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.line, e.column, e.msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.line, e.msg)
+}
+
+// errorAt creates a parse error at the byte offset of text
+func errorAt(text string, offset int, msg string) *parseError {
+	offset = min(max(offset, 0), len(text))
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return &parseError{line: line, column: column, msg: msg}
+}
+
+// dataKind is the type of a value in a JSON or YAML document
+type dataKind int
+
+const (
+	dataObject dataKind = iota
+	dataArray
+	dataString
+	dataNumber
+	dataBool
+	dataNull
+)
+
+// dataNode is a parsed value that keeps the order of object keys
+type dataNode struct {
+	kind     dataKind
+	key      string // Key within the parent object
+	value    string // Scalar value
+	children []*dataNode
+}
+
+// parseJSON parses a JSON document, keeping the order of keys
+func parseJSON(text string) (*dataNode, *parseError) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+
+	fail := func(err error) *parseError {
+		var syntax *json.SyntaxError
+		switch {
+		case errors.As(err, &syntax):
+			// The offset points past the offending character
+			return errorAt(text, int(syntax.Offset)-1, syntax.Error())
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return errorAt(text, len(text), "unexpected end of JSON input")
+		default:
+			return errorAt(text, int(dec.InputOffset()), err.Error())
+		}
+	}
+
+	var parse func(tok json.Token) (*dataNode, error)
+	parse = func(tok json.Token) (*dataNode, error) {
+		switch v := tok.(type) {
+		case json.Delim:
+			node := &dataNode{kind: dataObject}
+			if v == '[' {
+				node.kind = dataArray
+			}
+			for dec.More() {
+				var key string
+				if node.kind == dataObject {
+					keyTok, err := dec.Token()
+					if err != nil {
+						return nil, err
+					}
+					key, _ = keyTok.(string)
+				}
+
+				next, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := parse(next)
+				if err != nil {
+					return nil, err
+				}
+				child.key = key
+				node.children = append(node.children, child)
+			}
+			// Consume the closing delimiter
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case string:
+			return &dataNode{kind: dataString, value: v}, nil
+		case json.Number:
+			return &dataNode{kind: dataNumber, value: v.String()}, nil
+		case bool:
+			return &dataNode{kind: dataBool, value: strconv.FormatBool(v)}, nil
+		default:
+			return &dataNode{kind: dataNull, value: "null"}, nil
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fail(err)
+	}
+	root, err := parse(tok)
+	if err != nil {
+		return nil, fail(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errorAt(text, int(dec.InputOffset()), "unexpected data after top-level value")
+	}
+	return root, nil
+}
+
+// yamlErrorLine extracts the position from the messages of the YAML parser
+var yamlErrorLine = regexp.MustCompile(`line (\d+)(?:, column (\d+))?: (.*)`)
+
+// parseYAML parses every document of a YAML stream, keeping the order of keys
+func parseYAML(text string) ([]*dataNode, *parseError) {
+	dec := yaml.NewDecoder(strings.NewReader(text))
+
+	var docs []*dataNode
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			perr := &parseError{line: 1, msg: strings.TrimPrefix(err.Error(), "yaml: ")}
+			if match := yamlErrorLine.FindStringSubmatch(err.Error()); match != nil {
+				perr.line, _ = strconv.Atoi(match[1])
+				perr.column, _ = strconv.Atoi(match[2])
+				perr.msg = match[3]
+			}
+			return nil, perr
+		}
+		docs = append(docs, yamlToData(&doc))
+	}
+}
+
+// yamlToData converts a YAML node into the shared data representation
+func yamlToData(node *yaml.Node) *dataNode {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return &dataNode{kind: dataNull, value: "null"}
+		}
+		return yamlToData(node.Content[0])
+
+	case yaml.MappingNode:
+		out := &dataNode{kind: dataObject}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := yamlToData(node.Content[i+1])
+			child.key = node.Content[i].Value
+			out.children = append(out.children, child)
+		}
+		return out
+
+	case yaml.SequenceNode:
+		out := &dataNode{kind: dataArray}
+		for _, item := range node.Content {
+			out.children = append(out.children, yamlToData(item))
+		}
+		return out
+
+	case yaml.AliasNode:
+		return &dataNode{kind: dataString, value: "*" + node.Value}
+
+	default:
+		switch node.ShortTag() {
+		case "!!int", "!!float":
+			return &dataNode{kind: dataNumber, value: node.Value}
+		case "!!bool":
+			return &dataNode{kind: dataBool, value: node.Value}
+		case "!!null":
+			return &dataNode{kind: dataNull, value: node.Value}
+		default:
+			return &dataNode{kind: dataString, value: node.Value}
+		}
+	}
+}
+
+// dataWriter renders parsed data as indented lines of tokens
+type dataWriter struct {
+	yaml     bool // Write YAML instead of JSON syntax
+	depth    int  // Levels expanded before containers are collapsed
+	maxLines int  // Stop after this many lines, 0 for no limit
+	lines    [][]token
+}
+
+// full reports whether the line limit has been reached
+func (w *dataWriter) full() bool {
+	return w.maxLines > 0 && len(w.lines) >= w.maxLines
+}
+
+// add appends a line indented by level
+func (w *dataWriter) add(level int, tokens ...token) {
+	if w.full() {
+		return
+	}
+	line := []token{{tokenPlain, strings.Repeat("  ", level)}}
+	w.lines = append(w.lines, append(line, tokens...))
+}
+
+// scalar returns the token of a scalar value
+func (w *dataWriter) scalar(node *dataNode) token {
+	switch node.kind {
+	case dataString:
+		if w.yaml && !yamlNeedsQuotes(node.value) {
+			return token{tokenString, node.value}
+		}
+		return token{tokenString, strconv.Quote(node.value)}
+	case dataNumber:
+		return token{tokenNumber, node.value}
+	default:
+		return token{tokenType, node.value}
+	}
+}
+
+// collapsed returns the tokens shown for a container below the expanded depth
+func collapsed(node *dataNode) []token {
+	if node.kind == dataObject {
+		return []token{{tokenPlain, "{…}"}, {tokenComment, fmt.Sprintf(" %d keys", len(node.children))}}
+	}
+	return []token{{tokenPlain, "[…]"}, {tokenComment, fmt.Sprintf(" %d items", len(node.children))}}
+}
+
+// keyToken returns the token of an object key
+func (w *dataWriter) keyToken(key string) token {
+	if w.yaml && !yamlNeedsQuotes(key) {
+		return token{tokenKey, key}
+	}
+	return token{tokenKey, strconv.Quote(key)}
+}
+
+// writeJSON writes node in JSON syntax. head holds the tokens in front of the value
+// on its first line, such as its key.
+func (w *dataWriter) writeJSON(node *dataNode, level int, head []token, last bool) {
+	comma := []token{}
+	if !last {
+		comma = []token{{tokenPlain, ","}}
+	}
+
+	if node.kind != dataObject && node.kind != dataArray {
+		w.add(level, append(append(head, w.scalar(node)), comma...)...)
+		return
+	}
+
+	openDelim, closeDelim := "{", "}"
+	if node.kind == dataArray {
+		openDelim, closeDelim = "[", "]"
+	}
+	switch {
+	case len(node.children) == 0:
+		w.add(level, append(append(head, token{tokenPlain, openDelim + closeDelim}), comma...)...)
+		return
+	case level >= w.depth:
+		w.add(level, append(append(head, collapsed(node)...), comma...)...)
+		return
+	}
+
+	w.add(level, append(head, token{tokenPlain, openDelim})...)
+	for i, child := range node.children {
+		if w.full() {
+			return
+		}
+		var childHead []token
+		if node.kind == dataObject {
+			childHead = []token{w.keyToken(child.key), {tokenPlain, ": "}}
+		}
+		w.writeJSON(child, level+1, childHead, i == len(node.children)-1)
+	}
+	w.add(level, append([]token{{tokenPlain, closeDelim}}, comma...)...)
+}
+
+// writeYAML writes the children of a container in YAML block syntax
+func (w *dataWriter) writeYAML(node *dataNode, level int) {
+	for _, child := range node.children {
+		if w.full() {
+			return
+		}
+
+		head := []token{{tokenPlain, "-"}}
+		if node.kind == dataObject {
+			head = []token{w.keyToken(child.key), {tokenPlain, ":"}}
+		}
+
+		switch {
+		case child.kind != dataObject && child.kind != dataArray:
+			w.add(level, append(head, token{tokenPlain, " "}, w.scalar(child))...)
+		case len(child.children) == 0 && child.kind == dataObject:
+			w.add(level, append(head, token{tokenPlain, " {}"})...)
+		case len(child.children) == 0:
+			w.add(level, append(head, token{tokenPlain, " []"})...)
+		case level+1 >= w.depth:
+			w.add(level, append(append(head, token{tokenPlain, " "}), collapsed(child)...)...)
+		case node.kind == dataArray:
+			// The first entry of a nested container follows the dash on the same line
+			first := len(w.lines)
+			w.writeYAML(child, level+1)
+			if first < len(w.lines) {
+				w.lines[first][0].text = strings.Repeat("  ", level) + "- "
+			}
+		default:
+			w.add(level, head...)
+			w.writeYAML(child, level+1)
+		}
+	}
+}
+
+// yamlPlainScalar matches strings that can be written without quotes in YAML
+var yamlPlainScalar = regexp.MustCompile(`^[A-Za-z_/.][A-Za-z0-9_./ ()+=@-]*$`)
+
+// yamlNeedsQuotes reports whether s would be read back as something else without quotes
+func yamlNeedsQuotes(s string) bool {
+	if !yamlPlainScalar.MatchString(s) || strings.HasSuffix(s, " ") {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", ".inf", ".nan":
+		return true
+	}
+	return false
+}
+
+// summarize describes the top-level value of a document
+func summarize(node *dataNode) string {
+	switch node.kind {
+	case dataObject:
+		return fmt.Sprintf("object with %d keys", len(node.children))
+	case dataArray:
+		return fmt.Sprintf("array with %d items", len(node.children))
+	default:
+		return "scalar value"
+	}
+}
+
+// renderData renders documents as pretty-printed JSON or YAML with a summary header
+func renderData(docs []*dataNode, format string, opts PreviewOptions) string {
+	w := &dataWriter{
+		yaml:  format == "YAML",
+		depth: max(opts.Depth, 1),
+	}
+	if opts.Height > 0 {
+		// One more line than fits lets the preview show that there is more
+		w.maxLines = opts.Height + 1
+	}
+
+	summary := "empty document"
+	if len(docs) == 1 {
+		summary = summarize(docs[0])
+	} else if len(docs) > 1 {
+		summary = fmt.Sprintf("%d documents", len(docs))
+	}
+
+	for i, doc := range docs {
+		switch {
+		case !w.yaml:
+			w.writeJSON(doc, 0, nil, true)
+		case i > 0 || len(docs) > 1:
+			w.add(0, token{tokenComment, "---"})
+			fallthrough
+		default:
+			if doc.kind == dataObject || doc.kind == dataArray {
+				w.writeYAML(doc, 0)
+			} else {
+				w.add(0, w.scalar(doc))
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(opts.Theme.SyntaxStringStyle.Render("✓ "))
+	b.WriteString(opts.Theme.LineNumberStyle.Render(fmt.Sprintf("%s, %s (depth %d, +/- to change)", format, summary, w.depth)))
+	for _, line := range w.lines {
+		b.WriteByte('\n')
+		writeTokens(&b, line, opts.Theme, opts.Width)
+	}
+	return b.String()
+}
+
+// renderParseError renders the position and reason of a parse error above the source
+func renderParseError(text, format string, lang *language, perr *parseError, opts PreviewOptions) string {
+	header := opts.Theme.ErrorStyle.Render(fmt.Sprintf("✗ Invalid %s: %s", format, perr.Error()))
+	return header + "\n" + highlightSource(text, lang, opts.Theme, opts.Width, perr, previewLines(opts))
+}
+
+// renderTable renders delimited text as table with aligned columns below its header row
+func renderTable(text string, comma rune, opts PreviewOptions) string {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = comma
+
+	maxRows := -1
+	if opts.Height > 0 {
+		// Enough rows to fill the preview, which cuts off the rest
+		maxRows = opts.Height
+	}
+
+	var rows [][]string
+	for len(rows) != maxRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				lang := &language{quotes: `"`}
+				return renderParseError(text, "CSV", lang, &parseError{line: perr.Line, column: perr.Column, msg: perr.Err.Error()}, opts)
+			}
+			return opts.Theme.ErrorStyle.Render(fmt.Sprintf("✗ Invalid CSV: %v", err))
+		}
+		rows = append(rows, record)
+	}
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	// Size each column to its widest value, and keep as many columns as fit
+	columns := len(rows[0])
+	widths := make([]int, columns)
+	numeric := make([]bool, columns)
+	for i := range numeric {
+		numeric[i] = len(rows) > 1
+	}
+	for r, row := range rows {
+		for i, value := range row {
+			if i >= columns {
+				break
+			}
+			widths[i] = min(max(widths[i], utf8.RuneCountInString(value)), csvMaxColumnWidth)
+			if r > 0 && value != "" {
+				if _, err := strconv.ParseFloat(value, 64); err != nil {
+					numeric[i] = false
+				}
+			}
+		}
+	}
+
+	const separator = " │ "
+	shown, used := 0, 0
+	for shown < columns {
+		need := widths[shown]
+		if shown > 0 {
+			need += utf8.RuneCountInString(separator)
+		}
+		if opts.Width > 0 && used+need > opts.Width && shown > 0 {
+			break
+		}
+		used += need
+		shown++
+	}
+
+	theme := opts.Theme
+	var b strings.Builder
+
+	summary := fmt.Sprintf("%d columns, %d rows shown", columns, len(rows)-1)
+	if shown < columns {
+		summary += fmt.Sprintf(", %d columns hidden", columns-shown)
+	}
+	b.WriteString(theme.SyntaxStringStyle.Render("✓ "))
+	b.WriteString(theme.LineNumberStyle.Render(summary))
+
+	for r, row := range rows {
+		var cells []string
+		for i := 0; i < shown; i++ {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			value = truncateRunes(strings.ReplaceAll(value, "\n", " "), widths[i])
+
+			switch {
+			case r == 0:
+				cells = append(cells, theme.SyntaxKeyStyle.Render(padRight(value, widths[i])))
+			case numeric[i]:
+				cells = append(cells, theme.SyntaxNumberStyle.Render(padLeft(value, widths[i])))
+			default:
+				cells = append(cells, padRight(value, widths[i]))
+			}
+		}
+		b.WriteByte('\n')
+		b.WriteString(strings.Join(cells, theme.LineNumberStyle.Render(separator)))
+
+		if r == 0 {
+			var rules []string
+			for i := 0; i < shown; i++ {
+				rules = append(rules, strings.Repeat("─", widths[i]))
+			}
+			b.WriteByte('\n')
+			b.WriteString(theme.LineNumberStyle.Render(strings.Join(rules, "─┼─")))
+		}
+	}
+	return b.String()
+}
+
+// renderConfig validates a TOML or INI file and shows it highlighted,
+// with the position of the first error marked if it is invalid
+func renderConfig(text, format string, lang *language, validate func(string) *parseError, opts PreviewOptions) string {
+	if perr := validate(text); perr != nil {
+		return renderParseError(text, format, lang, perr, opts)
+	}
+
+	header := opts.Theme.SyntaxStringStyle.Render("✓ ") + opts.Theme.LineNumberStyle.Render("Valid "+format)
+	return header + "\n" + highlightSource(text, lang, opts.Theme, opts.Width, nil, previewLines(opts))
+}
+
+// previewLines returns how many lines of source are rendered for opts, or 0 for all of them.
+// One more line than fits lets the preview show that there is more.
+func previewLines(opts PreviewOptions) int {
+	if opts.Height > 0 {
+		return opts.Height + 1
+	}
+	return 0
+}
+
+// generateStructuredPreview parses a data file and renders it according to its format.
+// Files larger than structuredPreviewSize fall back to the highlighted text preview.
+func generateStructuredPreview(a *vfsadapter.Adapter, entry *vfsadapter.Entry, fileType vfsadapter.FileType, preview vfsadapter.PreviewType, opts PreviewOptions) (string, error) {
+	// Tables only show their first rows, so they may be cut off at the limit
+	if preview != vfsadapter.PreviewCSV && entry.Size > structuredPreviewSize {
+		return generateTextPreview(a, entry, fileType, opts)
+	}
+
+	text, ok, err := a.ReadText(entry.Path, structuredPreviewSize)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "[Binary file - cannot preview as text]", nil
+	}
+	if entry.Size > structuredPreviewSize {
+		// Drop the last row, which is most likely incomplete
+		text = text[:strings.LastIndexByte(text, '\n')+1]
+	}
+
+	// Many editors write a byte order mark in front of data files
+	text = strings.TrimPrefix(text, "\ufeff")
+	if strings.TrimSpace(text) == "" {
+		return text, nil
+	}
+	lang := detectLanguage(fileType, entry.Name, text)
+
+	switch preview {
+	case vfsadapter.PreviewJSON:
+		root, perr := parseJSON(text)
+		if perr != nil {
+			return renderParseError(text, "JSON", lang, perr, opts), nil
+		}
+		return renderData([]*dataNode{root}, "JSON", opts), nil
+
+	case vfsadapter.PreviewYAML:
+		docs, perr := parseYAML(text)
+		if perr != nil {
+			return renderParseError(text, "YAML", lang, perr, opts), nil
+		}
+		return renderData(docs, "YAML", opts), nil
+
+	case vfsadapter.PreviewCSV:
+		comma := ','
+		if fileType.MimeType == "text/tab-separated-values" {
+			comma = '\t'
+		} else if first, _, _ := strings.Cut(text, "\n"); strings.Count(first, ";") > strings.Count(first, ",") {
+			// Spreadsheets in many locales export with semicolons
+			comma = ';'
+		}
+		return renderTable(text, comma, opts), nil
+
+	case vfsadapter.PreviewTOML:
+		return renderConfig(text, "TOML", lang, validateTOML, opts), nil
+
+	default:
+		return renderConfig(text, "INI", lang, validateINI, opts), nil
+	}
+}
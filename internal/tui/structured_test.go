@@ -0,0 +1,57 @@
+package tui
+
+import "testing"
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		kind dataKind
+		keys []string // Keys of the root object in order
+		line int      // Line of the expected error, 0 if valid
+	}{
+		{"object keeps key order", `{"b": 1, "a": 2, "c": 3}`, dataObject, []string{"b", "a", "c"}, 0},
+		{"nested", "{\n  \"list\": [1, 2],\n  \"obj\": {\"x\": null}\n}", dataObject, []string{"list", "obj"}, 0},
+		{"array", `[1, "two", true]`, dataArray, nil, 0},
+		{"string", `"text"`, dataString, nil, 0},
+		{"number", `12.5`, dataNumber, nil, 0},
+		{"bool", `false`, dataBool, nil, 0},
+		{"null", `null`, dataNull, nil, 0},
+		{"missing comma", "{\n  \"a\": 1\n  \"b\": 2\n}", 0, nil, 3},
+		{"trailing comma", "[1,\n2,\n]", 0, nil, 2},
+		{"unterminated", "{\n  \"a\": [1, 2", 0, nil, 2},
+		{"trailing data", "{}\n{}", 0, nil, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, perr := parseJSON(tt.text)
+			if tt.line != 0 {
+				if perr == nil {
+					t.Fatalf("expected an error on line %d", tt.line)
+				}
+				if perr.line != tt.line {
+					t.Fatalf("error %q on line %d, want line %d", perr, perr.line, tt.line)
+				}
+				return
+			}
+
+			if perr != nil {
+				t.Fatalf("unexpected error: %v", perr)
+			}
+			if node.kind != tt.kind {
+				t.Fatalf("kind = %v, want %v", node.kind, tt.kind)
+			}
+			if tt.keys != nil {
+				if len(node.children) != len(tt.keys) {
+					t.Fatalf("got %d keys, want %d", len(node.children), len(tt.keys))
+				}
+				for i, key := range tt.keys {
+					if node.children[i].key != key {
+						t.Fatalf("key %d = %q, want %q", i, node.children[i].key, key)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/config"
+)
+
+// Tab holds an independent set of panes together with its layout and preview state
+type Tab struct {
+	// Panes
+	panes    [2]*Pane
+	active   int  // Index of the pane that receives input
+	dualPane bool // Show both panes side by side
+
+	// Listing
+	longListing bool // Show the configured columns next to each name
+
+	// Preview state
+	showPreview     bool
+	previewContent  string
+	previewError    error
+	previewGraphics bool // Preview content draws an image with a pixel protocol
+	previewDepth    int  // Levels of JSON and YAML shown expanded
+	rawPreview      bool // Show Markdown and data files as source
+}
+
+// NewTab creates a tab whose panes both start at path
+func NewTab(path string) *Tab {
+	return &Tab{
+		panes:        [2]*Pane{NewPane(path), NewPane(path)},
+		showPreview:  true,
+		previewDepth: defaultPreviewDepth,
+	}
+}
+
+// Title returns the short label shown in the title bar
+func (t *Tab) Title() string {
+	path := t.panes[t.active].currentPath
+	if path == "/" {
+		return path
+	}
+	return filepath.Base(path)
+}
+
+// tab returns the tab that is currently shown
+func (m *Model) tab() *Tab {
+	return m.tabs[m.tabIndex]
+}
+
+// openTab opens a new tab at the current directory and switches to it
+func (m *Model) openTab() tea.Cmd {
+	tab := NewTab(m.pane().currentPath)
+	tab.longListing = m.tab().longListing
+
+	m.tabIndex++
+	m.tabs = slices.Insert(m.tabs, m.tabIndex, tab)
+	return m.loadDirectory()
+}
+
+// closeTab closes the current tab unless it is the last one
+func (m *Model) closeTab() tea.Cmd {
+	if len(m.tabs) == 1 {
+		m.statusMsg = "Cannot close the last tab"
+		return nil
+	}
+
+	m.tabs = slices.Delete(m.tabs, m.tabIndex, m.tabIndex+1)
+	if m.tabIndex >= len(m.tabs) {
+		m.tabIndex = len(m.tabs) - 1
+	}
+	return m.switchTab(m.tabIndex)
+}
+
+// switchTab shows the tab at index, wrapping around at both ends
+func (m *Model) switchTab(index int) tea.Cmd {
+	m.tabIndex = (index + len(m.tabs)) % len(m.tabs)
+
+	// Other tabs were not refreshed while they were hidden
+	return tea.Batch(m.loadPanes(), m.updatePreview())
+}
+
+// moveTab moves the current tab by delta positions
+func (m *Model) moveTab(delta int) {
+	target := m.tabIndex + delta
+	if target < 0 || target >= len(m.tabs) {
+		return
+	}
+
+	m.tabs[m.tabIndex], m.tabs[target] = m.tabs[target], m.tabs[m.tabIndex]
+	m.tabIndex = target
+}
+
+// restoreSession recreates the tabs stored in session.
+// Directories that no longer exist fall back to the root directory.
+func (m *Model) restoreSession(session *config.Session) {
+	var tabs []*Tab
+	for _, st := range session.Tabs {
+		tab := NewTab("/")
+		for i, path := range st.Panes {
+			if i >= len(tab.panes) {
+				break
+			}
+			if path != "" && m.adapter.Exists(path) {
+				tab.panes[i].currentPath = path
+			}
+		}
+		if st.ActivePane == 1 {
+			tab.active = 1
+		}
+		tab.dualPane = st.DualPane
+		tab.showPreview = !st.HidePreview
+		tab.longListing = st.LongListing
+		tabs = append(tabs, tab)
+	}
+
+	if len(tabs) == 0 {
+		return
+	}
+
+	m.tabs = tabs
+	m.tabIndex = 0
+	if session.ActiveTab >= 0 && session.ActiveTab < len(tabs) {
+		m.tabIndex = session.ActiveTab
+	}
+}
+
+// SaveSession stores the open tabs so they are restored on the next start
+func (m *Model) SaveSession() error {
+	if m.sessionPath == "" {
+		return nil
+	}
+
+	session := &config.Session{ActiveTab: m.tabIndex}
+	for _, tab := range m.tabs {
+		session.Tabs = append(session.Tabs, &config.SessionTab{
+			Panes:       []string{tab.panes[0].currentPath, tab.panes[1].currentPath},
+			ActivePane:  tab.active,
+			DualPane:    tab.dualPane,
+			HidePreview: !tab.showPreview,
+			LongListing: tab.longListing,
+		})
+	}
+
+	return config.SaveSession(m.sessionPath, session)
+}
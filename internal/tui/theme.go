@@ -2,19 +2,53 @@ package tui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
 )
 
 const (
-	TextFile    string = "📑"
-	ImageFile   string = "🖼️"
-	VideoFile   string = "🎞️"
-	ArchiveFile string = "📦"
-	CodeFile    string = "📇"
-	MountFile   string = "🗃️"
-	FolderFile  string = "📂"
-	DefaultFile string = "📄"
+	TextFile     string = "📑"
+	ImageFile    string = "🖼️"
+	VideoFile    string = "🎞️"
+	AudioFile    string = "🎵"
+	ArchiveFile  string = "📦"
+	CodeFile     string = "📇"
+	DocumentFile string = "📕"
+	MountFile    string = "🗃️"
+	FolderFile   string = "📂"
+	DefaultFile  string = "📄"
 )
 
+// entryIcon returns an icon character based on the detected file type
+func entryIcon(e *vfsadapter.Entry) string {
+	// Check if it's a mount point first
+	if e.Mode.IsMount() {
+		return MountFile
+	}
+
+	if e.IsDir {
+		return FolderFile
+	}
+
+	switch e.FileType().Kind {
+	case vfsadapter.KindText:
+		return TextFile
+	case vfsadapter.KindCode:
+		return CodeFile
+	case vfsadapter.KindImage:
+		return ImageFile
+	case vfsadapter.KindVideo:
+		return VideoFile
+	case vfsadapter.KindAudio:
+		return AudioFile
+	case vfsadapter.KindArchive:
+		return ArchiveFile
+	case vfsadapter.KindDocument:
+		return DocumentFile
+	default:
+		return DefaultFile
+	}
+}
+
 // Theme defines the color scheme and styles for the TUI
 type Theme struct {
 	// Base colors
@@ -34,15 +68,36 @@ type Theme struct {
 	TitleStyle         lipgloss.Style
 	StatusBarStyle     lipgloss.Style
 	SelectedItemStyle  lipgloss.Style
+	MarkedItemStyle    lipgloss.Style
 	NormalItemStyle    lipgloss.Style
 	DirectoryStyle     lipgloss.Style
+	MountStyle         lipgloss.Style
 	FileStyle          lipgloss.Style
 	BorderStyle        lipgloss.Style
+	ActiveBorderStyle  lipgloss.Style
 	PreviewStyle       lipgloss.Style
 	PreviewBorderStyle lipgloss.Style
 	ErrorStyle         lipgloss.Style
 	HelpStyle          lipgloss.Style
 	CommandStyle       lipgloss.Style
+
+	// Syntax highlighting of previews
+	LineNumberStyle    lipgloss.Style
+	SyntaxKeywordStyle lipgloss.Style
+	SyntaxTypeStyle    lipgloss.Style
+	SyntaxStringStyle  lipgloss.Style
+	SyntaxNumberStyle  lipgloss.Style
+	SyntaxCommentStyle lipgloss.Style
+	SyntaxKeyStyle     lipgloss.Style
+
+	// Rendered Markdown
+	MarkdownH1Style    lipgloss.Style
+	MarkdownH2Style    lipgloss.Style
+	MarkdownH3Style    lipgloss.Style
+	MarkdownCodeStyle  lipgloss.Style
+	MarkdownLinkStyle  lipgloss.Style
+	MarkdownQuoteStyle lipgloss.Style
+	MarkdownListStyle  lipgloss.Style
 }
 
 // DefaultTheme returns a default dark theme
@@ -76,6 +131,10 @@ func DefaultTheme() *Theme {
 		Background(t.Highlight).
 		Bold(true)
 
+	t.MarkedItemStyle = lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true)
+
 	t.NormalItemStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground)
 
@@ -83,6 +142,10 @@ func DefaultTheme() *Theme {
 		Foreground(t.Primary).
 		Bold(true)
 
+	t.MountStyle = lipgloss.NewStyle().
+		Foreground(t.Secondary).
+		Bold(true)
+
 	t.FileStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground)
 
@@ -90,6 +153,10 @@ func DefaultTheme() *Theme {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(t.Border)
 
+	t.ActiveBorderStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary)
+
 	t.PreviewStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground).
 		Padding(1)
@@ -111,6 +178,9 @@ func DefaultTheme() *Theme {
 		Foreground(t.Success).
 		Bold(true)
 
+	t.setSyntaxStyles()
+	t.setMarkdownStyles()
+
 	return t
 }
 
@@ -145,6 +215,10 @@ func GruvboxTheme() *Theme {
 		Background(t.Highlight).
 		Bold(true)
 
+	t.MarkedItemStyle = lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true)
+
 	t.NormalItemStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground)
 
@@ -152,6 +226,10 @@ func GruvboxTheme() *Theme {
 		Foreground(t.Primary).
 		Bold(true)
 
+	t.MountStyle = lipgloss.NewStyle().
+		Foreground(t.Secondary).
+		Bold(true)
+
 	t.FileStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground)
 
@@ -159,6 +237,10 @@ func GruvboxTheme() *Theme {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(t.Border)
 
+	t.ActiveBorderStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Primary)
+
 	t.PreviewStyle = lipgloss.NewStyle().
 		Foreground(t.Foreground).
 		Padding(1)
@@ -180,5 +262,30 @@ func GruvboxTheme() *Theme {
 		Foreground(t.Success).
 		Bold(true)
 
+	t.setSyntaxStyles()
+	t.setMarkdownStyles()
+
 	return t
 }
+
+// setSyntaxStyles derives the syntax highlighting styles from the base colors
+func (t *Theme) setSyntaxStyles() {
+	t.LineNumberStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	t.SyntaxKeywordStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	t.SyntaxTypeStyle = lipgloss.NewStyle().Foreground(t.Primary)
+	t.SyntaxStringStyle = lipgloss.NewStyle().Foreground(t.Success)
+	t.SyntaxNumberStyle = lipgloss.NewStyle().Foreground(t.Warning)
+	t.SyntaxCommentStyle = lipgloss.NewStyle().Foreground(t.Dim).Italic(true)
+	t.SyntaxKeyStyle = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+}
+
+// setMarkdownStyles derives the styles of rendered Markdown from the base colors
+func (t *Theme) setMarkdownStyles() {
+	t.MarkdownH1Style = lipgloss.NewStyle().Foreground(t.Primary).Bold(true)
+	t.MarkdownH2Style = lipgloss.NewStyle().Foreground(t.Primary).Bold(true).Underline(true)
+	t.MarkdownH3Style = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+	t.MarkdownCodeStyle = lipgloss.NewStyle().Foreground(t.Warning)
+	t.MarkdownLinkStyle = lipgloss.NewStyle().Foreground(t.Primary).Underline(true)
+	t.MarkdownQuoteStyle = lipgloss.NewStyle().Foreground(t.Secondary)
+	t.MarkdownListStyle = lipgloss.NewStyle().Foreground(t.Secondary).Bold(true)
+}
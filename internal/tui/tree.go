@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// treeState holds the expanded directories of a pane that is shown as tree
+type treeState struct {
+	children map[string][]*vfsadapter.Entry // Listings of the root and every expanded directory
+	prefix   map[string]string              // Guide lines drawn in front of each visible node
+	depth    map[string]int                 // Nesting level of each visible node below the root
+}
+
+// newTreeState creates a tree with nothing expanded
+func newTreeState() *treeState {
+	return &treeState{
+		children: make(map[string][]*vfsadapter.Entry),
+		prefix:   make(map[string]string),
+		depth:    make(map[string]int),
+	}
+}
+
+// isExpanded reports whether the children of dir are shown
+func (t *treeState) isExpanded(dir string) bool {
+	_, ok := t.children[dir]
+	return ok
+}
+
+// collapse hides dir together with everything expanded below it
+func (t *treeState) collapse(dir string) {
+	delete(t.children, dir)
+	for path := range t.children {
+		if strings.HasPrefix(path, dir+"/") {
+			delete(t.children, path)
+		}
+	}
+}
+
+// flatten lists the children of root depth-first, descending into expanded directories
+func (t *treeState) flatten(root string) []*vfsadapter.Entry {
+	t.prefix = make(map[string]string)
+	t.depth = make(map[string]int)
+
+	var entries []*vfsadapter.Entry
+	var walk func(dir string, depth int, guide string)
+	walk = func(dir string, depth int, guide string) {
+		children := t.children[dir]
+		for i, entry := range children {
+			prefix, next := guide, guide
+			if depth > 0 {
+				if i == len(children)-1 {
+					prefix += "└─ "
+					next += "   "
+				} else {
+					prefix += "├─ "
+					next += "│  "
+				}
+			}
+
+			entries = append(entries, entry)
+			t.prefix[entry.Path] = prefix
+			t.depth[entry.Path] = depth
+
+			if entry.IsDir && t.isExpanded(entry.Path) {
+				walk(entry.Path, depth+1, next)
+			}
+		}
+	}
+	walk(root, 0, "")
+
+	return entries
+}
+
+// expandedDirs returns every expanded directory below the root of the tree
+func (p *Pane) expandedDirs() []string {
+	if p.tree == nil {
+		return nil
+	}
+
+	dirs := make([]string, 0, len(p.tree.children))
+	for dir := range p.tree.children {
+		if dir != p.currentPath {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// treePrefix returns the guide lines and expansion marker shown in front of entry
+func (p *Pane) treePrefix(entry *vfsadapter.Entry) string {
+	if p.tree == nil {
+		return ""
+	}
+
+	prefix := p.tree.prefix[entry.Path]
+	switch {
+	case !entry.IsDir:
+		return prefix + "  "
+	case p.tree.isExpanded(entry.Path):
+		return prefix + "▾ "
+	default:
+		return prefix + "▸ "
+	}
+}
+
+// rebuildTree flattens the tree again and keeps the cursor on the same node
+func (p *Pane) rebuildTree(visibleLines int) {
+	current := p.currentEntry()
+	p.commitVisual()
+
+	p.allEntries = p.tree.flatten(p.currentPath)
+	if err := p.applyFilter(); err != nil {
+		p.entries = p.allEntries
+	}
+	p.pruneSelection()
+	p.focusEntry(current, visibleLines)
+}
+
+// workingDirectory returns where new files are created and pasted.
+// In tree view this is the highlighted directory or the directory holding the highlighted file.
+func (p *Pane) workingDirectory() string {
+	if p.tree == nil {
+		return p.currentPath
+	}
+
+	entry := p.currentEntry()
+	switch {
+	case entry == nil:
+		return p.currentPath
+	case entry.IsDir:
+		return entry.Path
+	default:
+		return filepath.Dir(entry.Path)
+	}
+}
+
+// treeLoadedMsg delivers the children of a directory expanded in tree view
+type treeLoadedMsg struct {
+	pane    *Pane
+	root    string
+	dir     string
+	entries []*vfsadapter.Entry
+}
+
+// toggleTreeView switches the active pane between the flat list and the tree
+func (m *Model) toggleTreeView() tea.Cmd {
+	p := m.pane()
+	if p.tree == nil {
+		p.tree = newTreeState()
+		m.statusMsg = "Tree view"
+	} else {
+		p.tree = nil
+		m.statusMsg = "List view"
+	}
+	return m.loadDirectory()
+}
+
+// toggleNode expands or collapses the directory under the cursor
+func (m *Model) toggleNode(entry *vfsadapter.Entry) tea.Cmd {
+	p := m.pane()
+	if p.tree.isExpanded(entry.Path) {
+		p.tree.collapse(entry.Path)
+		p.rebuildTree(m.getPaneLines())
+		return m.updatePreview()
+	}
+
+	root := p.currentPath
+	settings := m.sortSettings(root)
+
+	return func() tea.Msg {
+		entries, err := m.adapter.ListDirectory(entry.Path)
+		if err != nil {
+			return errorMsg(fmt.Sprintf("Failed to load directory: %v", err))
+		}
+		m.adapter.SniffEntries(entries)
+		sortEntries(entries, settings)
+		return treeLoadedMsg{pane: p, root: root, dir: entry.Path, entries: entries}
+	}
+}
+
+// collapseNode collapses the expanded directory under the cursor or moves the cursor
+// to the parent node. It reports false at the top level, where back leaves the directory.
+func (m *Model) collapseNode() bool {
+	p := m.pane()
+	entry := p.currentEntry()
+	if entry == nil {
+		return false
+	}
+
+	if entry.IsDir && p.tree.isExpanded(entry.Path) {
+		p.tree.collapse(entry.Path)
+		p.rebuildTree(m.getPaneLines())
+		return true
+	}
+
+	if p.tree.depth[entry.Path] == 0 {
+		return false
+	}
+
+	parent := filepath.Dir(entry.Path)
+	for _, e := range p.entries {
+		if e.Path == parent {
+			p.focusEntry(e, m.getPaneLines())
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
+)
+
+// usageBarWidth is the width of the bar drawn in front of each entry
+const usageBarWidth = 20
+
+// UsageResults holds the result of the most recent disk usage walk.
+// The tree is set once by the job and only changed by the view afterwards.
+type UsageResults struct {
+	Root string
+
+	mu   sync.Mutex
+	tree *vfsadapter.UsageNode
+
+	current  *vfsadapter.UsageNode // Directory shown in the view
+	cursor   int
+	confirm  *vfsadapter.UsageNode         // Entry waiting for delete confirmation
+	deleting map[int]*vfsadapter.UsageNode // Entries being deleted, keyed by job
+}
+
+// set stores the finished tree
+func (r *UsageResults) set(tree *vfsadapter.UsageNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tree = tree
+}
+
+// Tree returns the finished tree, or nil while the walk is still running
+func (r *UsageResults) Tree() *vfsadapter.UsageNode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tree
+}
+
+// deleted detaches the entry deleted by job from the tree once the job succeeded
+func (r *UsageResults) deleted(job *Job) {
+	node, ok := r.deleting[job.ID]
+	if !ok {
+		return
+	}
+	delete(r.deleting, job.ID)
+	if job.State != JobDone {
+		return
+	}
+
+	// Leave the deleted directory if it is currently shown
+	for n := r.current; n != nil; n = n.Parent {
+		if n == node {
+			r.current, r.cursor = node.Parent, 0
+			break
+		}
+	}
+
+	node.Remove()
+	if r.current != nil && r.cursor >= len(r.current.Children) {
+		r.cursor = max(len(r.current.Children)-1, 0)
+	}
+}
+
+// startUsage walks the current directory as background job and opens the usage view
+func (m *Model) startUsage() tea.Cmd {
+	root := m.pane().currentPath
+	results := &UsageResults{Root: root}
+	m.usage = results
+
+	job := m.startJob(fmt.Sprintf("Disk usage %s", root), func(adapter *vfsadapter.Adapter, report vfsadapter.ProgressFunc) (string, error) {
+		tree, err := adapter.DiskUsage(root, vfsadapter.UsageOptions{}, report)
+		if err != nil {
+			return "", err
+		}
+		results.set(tree)
+		return fmt.Sprintf("%s in %d file(s) below %s", vfsadapter.FormatSize(tree.Size), tree.Files, root), nil
+	})
+	m.usageJobID = job.ID
+	m.mode = ModeUsage
+
+	return nil
+}
+
+// usageNode returns the directory shown in the usage view, or nil while scanning
+func (m *Model) usageNode() *vfsadapter.UsageNode {
+	if m.usage == nil {
+		return nil
+	}
+
+	if m.usage.current == nil {
+		m.usage.current = m.usage.Tree()
+	}
+	return m.usage.current
+}
+
+// handleUsageMode processes keys in the disk usage view
+func (m *Model) handleUsageMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	node := m.usageNode()
+
+	// A pending delete is confirmed with y, any other key aborts it
+	if m.usage != nil && m.usage.confirm != nil {
+		target := m.usage.confirm
+		m.usage.confirm = nil
+
+		if strings.ToLower(msg.String()) != "y" {
+			return m, nil
+		}
+
+		// The entry stays in the tree until the delete has succeeded
+		if m.usage.deleting == nil {
+			m.usage.deleting = make(map[int]*vfsadapter.UsageNode)
+		}
+		job := m.startDelete([]*vfsadapter.Entry{target.Entry})
+		m.usage.deleting[job.ID] = target
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.DiskUsage), key.Matches(msg, m.keys.Quit), msg.Type == tea.KeyEscape:
+		m.mode = ModeNormal
+		return m, nil
+
+	case key.Matches(msg, m.keys.CancelJob):
+		m.jobs.Cancel(m.usageJobID)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Refresh):
+		return m, m.startUsage()
+	}
+
+	if node == nil {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.usage.cursor = max(m.usage.cursor-1, 0)
+
+	case key.Matches(msg, m.keys.Down):
+		m.usage.cursor = max(min(m.usage.cursor+1, len(node.Children)-1), 0)
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.usage.cursor = max(m.usage.cursor-10, 0)
+
+	case key.Matches(msg, m.keys.PageDown):
+		m.usage.cursor = max(min(m.usage.cursor+10, len(node.Children)-1), 0)
+
+	case key.Matches(msg, m.keys.Top):
+		m.usage.cursor = 0
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.usage.cursor = max(len(node.Children)-1, 0)
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.usage.cursor < len(node.Children) {
+			if child := node.Children[m.usage.cursor]; child.Entry.IsDir {
+				m.usage.current = child
+				m.usage.cursor = 0
+			}
+		}
+
+	case key.Matches(msg, m.keys.Back):
+		if node.Parent != nil {
+			// Keep the directory we came from selected
+			m.usage.current = node.Parent
+			m.usage.cursor = 0
+			for i, child := range node.Parent.Children {
+				if child == node {
+					m.usage.cursor = i
+					break
+				}
+			}
+		}
+
+	case key.Matches(msg, m.keys.Delete):
+		if m.usage.cursor < len(node.Children) {
+			m.usage.confirm = node.Children[m.usage.cursor]
+		}
+	}
+
+	return m, nil
+}
+
+// renderUsageView renders the full-screen disk usage of the current directory
+func (m *Model) renderUsageView() string {
+	var sections []string
+
+	node := m.usageNode()
+
+	header := "VFS Disk Usage - Press U to return to Navigation"
+	if node != nil {
+		header = fmt.Sprintf("VFS Disk Usage - %s - %s in %d file(s) - Press U to return to Navigation",
+			node.Entry.Path, vfsadapter.FormatSize(node.Size), node.Files)
+	}
+	sections = append(sections, m.theme.TitleStyle.Render(header))
+
+	availableHeight := m.height - 6 // Reserve for title, help, padding
+
+	var lines []string
+	switch {
+	case node == nil:
+		lines = append(lines, m.theme.NormalItemStyle.Render(m.usageState()))
+	case len(node.Children) == 0:
+		lines = append(lines, m.theme.NormalItemStyle.Render("(empty directory)"))
+	}
+
+	if node != nil {
+		// Keep the selected entry in view
+		start := 0
+		if m.usage.cursor >= availableHeight {
+			start = m.usage.cursor - availableHeight + 1
+		}
+		end := min(start+availableHeight, len(node.Children))
+
+		for i := start; i < end; i++ {
+			lines = append(lines, m.renderUsageEntry(node, node.Children[i], i == m.usage.cursor))
+		}
+	}
+
+	sections = append(sections, m.theme.BorderStyle.
+		Width(m.width-4).
+		Height(availableHeight).
+		Render(strings.Join(lines, "\n")))
+
+	if m.usage != nil && m.usage.confirm != nil {
+		prompt := fmt.Sprintf("Delete %s (%s)? (y/n)", m.usage.confirm.Entry.Path, vfsadapter.FormatSize(m.usage.confirm.Size))
+		sections = append(sections, m.theme.ErrorStyle.Render(prompt))
+	} else {
+		sections = append(sections, m.theme.HelpStyle.Render("↑/↓ select • enter open • backspace up • d delete • ctrl+r rescan • ctrl+x cancel scan • U/esc back"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// usageState describes the walk while no tree is available yet
+func (m *Model) usageState() string {
+	job := m.jobs.Get(m.usageJobID)
+	switch {
+	case job == nil:
+		return "(no scan)"
+	case job.State == JobRunning:
+		return fmt.Sprintf("Scanning... %d file(s), %s - %s",
+			job.Progress.Files, vfsadapter.FormatSize(job.Progress.Bytes), job.Progress.Path)
+	case job.State == JobFailed:
+		return fmt.Sprintf("Scan failed: %v", job.Err)
+	default:
+		return fmt.Sprintf("Scan %s", job.State)
+	}
+}
+
+// renderUsageEntry renders one entry with its size, share of the directory and a bar
+// scaled to the largest entry
+func (m *Model) renderUsageEntry(parent, node *vfsadapter.UsageNode, selected bool) string {
+	percent := 0.0
+	if parent.Size > 0 {
+		percent = float64(node.Size) * 100 / float64(parent.Size)
+	}
+
+	filled := 0
+	if largest := parent.Children[0].Size; largest > 0 {
+		filled = int(node.Size * usageBarWidth / largest)
+	}
+	bar := strings.Repeat("#", filled) + strings.Repeat(" ", usageBarWidth-filled)
+
+	name := node.Entry.DisplayName()
+	if node.Err != nil {
+		name += " (unreadable)"
+	}
+
+	line := fmt.Sprintf("%10s %5.1f%% [%s] %s %s", vfsadapter.FormatSize(node.Size), percent, bar, entryIcon(node.Entry), name)
+
+	switch {
+	case selected:
+		return m.theme.SelectedItemStyle.Render(line)
+	case node.Err != nil:
+		return m.theme.ErrorStyle.Render(line)
+	case node.Entry.Mode.IsMount():
+		return m.theme.MountStyle.Render(line)
+	case node.Entry.IsDir:
+		return m.theme.DirectoryStyle.Render(line)
+	default:
+		return m.theme.FileStyle.Render(line)
+	}
+}
@@ -3,8 +3,11 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mwantia/vfsh/internal/vfsadapter"
 )
 
 // View renders the TUI
@@ -18,6 +21,14 @@ func (m *Model) View() string {
 		return m.renderHelp()
 	case ModeTerminal:
 		return m.renderTerminalView()
+	case ModeJobs:
+		return m.renderJobsView()
+	case ModeSearch:
+		return m.renderSearchView()
+	case ModeUsage:
+		return m.renderUsageView()
+	case ModeReader:
+		return m.renderReaderView()
 	default:
 		return m.renderMain()
 	}
@@ -30,14 +41,21 @@ func (m *Model) renderMain() string {
 	// Title bar
 	sections = append(sections, m.renderTitle())
 
-	// Main content area (file list + preview)
-	sections = append(sections, m.renderContent())
+	// Main content area (file list + preview), or one of the popups
+	switch m.mode {
+	case ModeJumpList:
+		sections = append(sections, m.renderJumpList())
+	case ModeFinder:
+		sections = append(sections, m.renderFinder())
+	default:
+		sections = append(sections, m.renderContent())
+	}
 
 	// Status bar
 	sections = append(sections, m.renderStatus())
 
 	// Input area (if in non-terminal input mode)
-	if m.mode == ModeInput {
+	if m.mode == ModeInput || m.mode == ModeFilter {
 		sections = append(sections, m.renderInput())
 	}
 
@@ -47,22 +65,45 @@ func (m *Model) renderMain() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
-// renderTitle renders the title bar with current path
+// renderTitle renders the title bar with current path and the open tabs
 func (m *Model) renderTitle() string {
-	title := fmt.Sprintf("VFS File Manager - %s", m.currentPath)
-	return m.theme.TitleStyle.Render(title)
+	title := fmt.Sprintf("VFS File Manager - %s", m.pane().currentPath)
+	if len(m.tabs) == 1 {
+		return m.theme.TitleStyle.Render(title)
+	}
+
+	tabs := make([]string, 0, len(m.tabs))
+	for i, tab := range m.tabs {
+		label := fmt.Sprintf(" %d:%s ", i+1, tab.Title())
+		if i == m.tabIndex {
+			tabs = append(tabs, m.theme.SelectedItemStyle.Render(label))
+		} else {
+			tabs = append(tabs, m.theme.NormalItemStyle.Render(label))
+		}
+	}
+
+	return m.theme.TitleStyle.Render(title) + " " + strings.Join(tabs, " ")
 }
 
 // renderContent renders the file list and preview pane
 func (m *Model) renderContent() string {
-	if m.showPreview {
-		// Split view: file list on left, preview on right
-		fileList := m.renderFileList()
-		preview := m.renderPreview()
+	return m.graphicsReset() + m.renderLayout()
+}
 
+// renderLayout renders the file lists and preview of the current tab
+func (m *Model) renderLayout() string {
+	if m.tab().dualPane {
+		return m.renderPanes()
+	}
+
+	if m.tab().showPreview {
+		// Split view: file list on left, preview on right
 		leftWidth := m.width / 2
 		rightWidth := m.width - leftWidth - 4 // Account for borders
 
+		fileList := m.renderFileList(m.pane(), leftWidth)
+		preview := m.renderPreview()
+
 		fileListBox := m.theme.BorderStyle.
 			Width(leftWidth).
 			Height(m.getVisibleLines() + 2).
@@ -77,41 +118,161 @@ func (m *Model) renderContent() string {
 	}
 
 	// Full width file list
-	fileList := m.renderFileList()
+	fileList := m.renderFileList(m.pane(), m.width-4)
 	return m.theme.BorderStyle.
 		Width(m.width - 4).
 		Height(m.getVisibleLines() + 2).
 		Render(fileList)
 }
 
-// renderFileList renders the list of files and directories
-func (m *Model) renderFileList() string {
-	if len(m.entries) == 0 {
+// renderPanes renders both panes side by side, each headed by its path
+func (m *Model) renderPanes() string {
+	leftWidth := m.width / 2
+	rightWidth := m.width - leftWidth - 4 // Account for borders
+	widths := [2]int{leftWidth, rightWidth}
+
+	var boxes []string
+	for i, p := range m.tab().panes {
+		active := i == m.tab().active
+
+		header := m.theme.DirectoryStyle.Render(p.currentPath)
+		style := m.theme.BorderStyle
+		if active {
+			header = m.theme.TitleStyle.UnsetPadding().Render(p.currentPath)
+			style = m.theme.ActiveBorderStyle
+		}
+
+		content := header + "\n" + m.renderFileList(p, widths[i])
+		boxes = append(boxes, style.
+			Width(widths[i]).
+			Height(m.getVisibleLines()+2).
+			Render(content))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, boxes...)
+}
+
+// renderJumpList renders the visited directories of the active pane
+func (m *Model) renderJumpList() string {
+	list, current := m.pane().jumpList()
+	visibleLines := m.getVisibleLines() - 1
+
+	// Keep the selected entry in view
+	start := 0
+	if m.jumpCursor >= visibleLines {
+		start = m.jumpCursor - visibleLines + 1
+	}
+	end := min(start+visibleLines, len(list))
+
+	lines := []string{m.theme.TitleStyle.UnsetPadding().Render("Jump list - enter to jump, esc to close")}
+	for i := start; i < end; i++ {
+		marker := " "
+		if i == current {
+			marker = ">"
+		}
+		line := fmt.Sprintf("%s %3d  %s", marker, i-current, list[i])
+
+		if i == m.jumpCursor {
+			lines = append(lines, m.theme.SelectedItemStyle.Render(line))
+		} else {
+			lines = append(lines, m.theme.DirectoryStyle.Render(line))
+		}
+	}
+
+	return m.theme.ActiveBorderStyle.
+		Width(m.width - 4).
+		Height(m.getVisibleLines() + 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderFinder renders the fuzzy finder query and its best matches
+func (m *Model) renderFinder() string {
+	f := m.finder
+
+	state := fmt.Sprintf("%d/%d", len(f.results), len(f.index))
+	if f.indexing {
+		state += " (indexing...)"
+	}
+
+	lines := []string{
+		m.theme.CommandStyle.Render("> ") + m.textInput.View(),
+		m.theme.HelpStyle.UnsetPadding().Render(state),
+	}
+	if f.err != nil {
+		lines = append(lines, m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", f.err)))
+	}
+
+	visibleLines := m.getVisibleLines() - len(lines)
+
+	// Keep the selected entry in view
+	start := 0
+	if f.cursor >= visibleLines {
+		start = f.cursor - visibleLines + 1
+	}
+	end := min(start+visibleLines, len(f.results))
+
+	for i := start; i < end; i++ {
+		entry := f.results[i]
+		line := fmt.Sprintf("%s %s", entryIcon(entry), entry.Path)
+		if entry.IsDir {
+			line += "/"
+		}
+
+		switch {
+		case i == f.cursor:
+			lines = append(lines, m.theme.SelectedItemStyle.Render(line))
+		case entry.IsDir:
+			lines = append(lines, m.theme.DirectoryStyle.Render(line))
+		default:
+			lines = append(lines, m.theme.FileStyle.Render(line))
+		}
+	}
+
+	return m.theme.ActiveBorderStyle.
+		Width(m.width - 4).
+		Height(m.getVisibleLines() + 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderFileList renders the list of files and directories of a pane into width cells
+func (m *Model) renderFileList(p *Pane, width int) string {
+	if len(p.entries) == 0 {
+		if p.filter != "" {
+			return m.theme.NormalItemStyle.Render("(no matches)")
+		}
 		return m.theme.NormalItemStyle.Render("(empty directory)")
 	}
 
 	var lines []string
-	visibleLines := m.getVisibleLines()
+	visibleLines := m.getPaneLines()
 
-	start := m.offset
-	end := m.offset + visibleLines
-	if end > len(m.entries) {
-		end = len(m.entries)
+	start := p.offset
+	end := p.offset + visibleLines
+	if end > len(p.entries) {
+		end = len(p.entries)
 	}
 
+	// Only the active pane highlights its cursor
+	cursor := -1
+	if p == m.pane() {
+		cursor = p.cursor
+	}
+
+	now := time.Now()
 	for i := start; i < end; i++ {
-		entry := m.entries[i]
-		line := m.renderFileEntry(entry, i == m.cursor)
+		entry := p.entries[i]
+		line := m.renderFileEntry(entry, p.treePrefix(entry), i == cursor, p.isMarked(i), width, now)
 		lines = append(lines, line)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
-// renderFileEntry renders a single file or directory entry
-func (m *Model) renderFileEntry(entry *Entry, selected bool) string {
+// renderFileEntry renders a single file or directory entry,
+// indented by the guide lines of the tree view in prefix
+func (m *Model) renderFileEntry(entry *vfsadapter.Entry, prefix string, selected, marked bool, width int, now time.Time) string {
 	// Build entry line: [icon] name size
-	icon := entry.Icon()
+	icon := entryIcon(entry)
 	name := entry.DisplayName()
 	size := entry.DisplaySize()
 
@@ -119,32 +280,44 @@ func (m *Model) renderFileEntry(entry *Entry, selected bool) string {
 	var style lipgloss.Style
 	if selected {
 		style = m.theme.SelectedItemStyle
+	} else if marked {
+		style = m.theme.MarkedItemStyle
+	} else if entry.Mode.IsMount() {
+		style = m.theme.MountStyle
 	} else if entry.IsDir {
 		style = m.theme.DirectoryStyle
 	} else {
 		style = m.theme.FileStyle
 	}
 
+	mark := " "
+	if marked {
+		mark = "*"
+	}
+
+	// The long listing fits its columns into the width left after mark and icon
+	if m.tab().longListing {
+		nameWidth := width - 4 - runewidth.StringWidth(prefix)
+		line := fmt.Sprintf("%s%s%s %s", mark, prefix, icon, m.formatLongEntry(entry, nameWidth, now))
+		return style.Render(line)
+	}
+
 	// Format: "📁 documents/        <DIR>"
 	nameWidth := 40
-	if m.showPreview {
+	if m.tab().showPreview || m.tab().dualPane {
 		nameWidth = 30
 	}
+	nameWidth -= runewidth.StringWidth(prefix)
 
-	formattedName := name
-	if len(name) > nameWidth {
-		formattedName = name[:nameWidth-3] + "..."
-	} else {
-		formattedName = name + strings.Repeat(" ", nameWidth-len(name))
-	}
+	formattedName := padRight(name, nameWidth)
 
-	line := fmt.Sprintf("%s %s %10s", icon, formattedName, size)
+	line := fmt.Sprintf("%s%s%s %s %10s", mark, prefix, icon, formattedName, size)
 	return style.Render(line)
 }
 
 // renderPreview renders the file preview pane
 func (m *Model) renderPreview() string {
-	entry := m.currentEntry()
+	entry := m.pane().currentEntry()
 	if entry == nil {
 		return m.theme.PreviewStyle.Render("No file selected")
 	}
@@ -159,11 +332,11 @@ func (m *Model) renderPreview() string {
 	}
 
 	// Show file preview
-	if m.previewError != nil {
-		return m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.previewError))
+	if m.tab().previewError != nil {
+		return m.theme.ErrorStyle.Render(fmt.Sprintf("Error: %v", m.tab().previewError))
 	}
 
-	if m.previewContent == "" {
+	if m.tab().previewContent == "" {
 		return m.theme.PreviewStyle.Render("(empty file)")
 	}
 
@@ -174,7 +347,7 @@ func (m *Model) renderPreview() string {
 	info += "--- Preview ---\n"
 
 	// Limit preview lines
-	lines := strings.Split(m.previewContent, "\n")
+	lines := strings.Split(m.tab().previewContent, "\n")
 	maxLines := m.getVisibleLines() - 6
 	if len(lines) > maxLines {
 		lines = lines[:maxLines]
@@ -188,13 +361,33 @@ func (m *Model) renderPreview() string {
 
 // renderStatus renders the status bar
 func (m *Model) renderStatus() string {
+	p := m.pane()
+
 	// Left side: file count and cursor position
 	left := ""
-	if len(m.entries) > 0 {
-		left = fmt.Sprintf("%d/%d items", m.cursor+1, len(m.entries))
+	if len(p.entries) > 0 {
+		left = fmt.Sprintf("%d/%d items", p.cursor+1, len(p.entries))
 	} else {
 		left = "0 items"
 	}
+	if p.visualAnchor >= 0 {
+		left += " | VISUAL"
+	}
+	if marked, hidden := p.markedCount(); hidden > 0 {
+		left += fmt.Sprintf(" | %d marked (%d hidden)", marked, hidden)
+	} else if marked > 0 {
+		left += fmt.Sprintf(" | %d marked", marked)
+	}
+	if p.filter != "" {
+		left += fmt.Sprintf(" | filter: %s (%d/%d)", p.filter, len(p.entries), len(p.allEntries))
+	}
+	left += " | sort: " + describeSort(m.sortSettings(p.currentPath))
+	if p.tree != nil {
+		left += " | tree"
+	}
+	if summary := m.jobs.Summary(); summary != "" {
+		left += " | " + summary
+	}
 
 	// Right side: status/error messages
 	right := ""
@@ -214,8 +407,11 @@ func (m *Model) renderStatus() string {
 // renderInput renders the input field for commands or user input
 func (m *Model) renderInput() string {
 	prompt := ""
-	if m.mode == ModeCommand {
+	switch m.mode {
+	case ModeCommand:
 		prompt = ": "
+	case ModeFilter:
+		prompt = "/"
 	}
 
 	input := prompt + m.textInput.View()
@@ -274,7 +470,7 @@ func (m *Model) renderTerminalContent() string {
 	// Current input prompt
 	currentPrompt := fmt.Sprintf("[%d] %s %s",
 		m.commandCounter,
-		m.theme.DirectoryStyle.Render(m.currentPath),
+		m.theme.DirectoryStyle.Render(m.pane().currentPath),
 		m.textInput.View(),
 	)
 	lines = append(lines, currentPrompt)
@@ -300,6 +496,73 @@ func (m *Model) renderTerminalContent() string {
 		Render(content)
 }
 
+// renderJobsView renders the full-screen list of background jobs
+func (m *Model) renderJobsView() string {
+	var sections []string
+
+	title := m.theme.TitleStyle.Render("VFS Jobs - Press J to return to Navigation")
+	sections = append(sections, title)
+
+	jobs := m.jobs.Jobs()
+	availableHeight := m.height - 6 // Reserve for title, help, padding
+
+	var lines []string
+	if len(jobs) == 0 {
+		lines = append(lines, m.theme.NormalItemStyle.Render("(no jobs)"))
+	}
+
+	for i, job := range jobs {
+		line := m.renderJob(job)
+		if i == m.jobCursor {
+			line = m.theme.SelectedItemStyle.Render(line)
+		} else if job.State == JobFailed {
+			line = m.theme.ErrorStyle.Render(line)
+		} else {
+			line = m.theme.NormalItemStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) > availableHeight {
+		lines = lines[len(lines)-availableHeight:]
+	}
+
+	sections = append(sections, m.theme.BorderStyle.
+		Width(m.width-4).
+		Height(availableHeight).
+		Render(strings.Join(lines, "\n")))
+
+	sections = append(sections, m.theme.HelpStyle.Render("↑/↓ select • ctrl+x cancel job • J/esc back"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderJob renders a single line in the jobs view
+func (m *Model) renderJob(job *Job) string {
+	const barWidth = 20
+
+	percent := job.Percent()
+	if job.State == JobDone {
+		percent = 100
+	}
+	filled := percent * barWidth / 100
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+	details := fmt.Sprintf("%d/%d files, %s", job.Progress.Files, job.Progress.TotalFiles, vfsadapter.FormatSize(job.Progress.Bytes))
+	switch job.State {
+	case JobRunning:
+		if eta := job.ETA(); eta > 0 {
+			details += fmt.Sprintf(", ETA %s", eta)
+		}
+	case JobFailed:
+		details = job.Err.Error()
+	default:
+		details = job.EndTime.Sub(job.StartTime).Round(time.Millisecond).String()
+	}
+
+	return fmt.Sprintf("#%-3d %-9s %s %3d%%  %-30s %s", job.ID, job.State, bar, percent, job.Description, details)
+}
+
 // renderHelpBar renders the bottom help bar
 func (m *Model) renderHelpBar() string {
 	if m.showFullHelp {
@@ -325,23 +588,79 @@ func (m *Model) renderHelp() string {
 	sections = append(sections, "  PgDn/Ctrl+D  Page down")
 	sections = append(sections, "  Home/g     Go to top")
 	sections = append(sections, "  End/G      Go to bottom")
-	sections = append(sections, "  Enter/l    Enter directory / Open file")
+	sections = append(sections, "  Enter/l    Enter directory or archive (read-only) / Open file in the full-screen reader")
 	sections = append(sections, "  Backspace/h  Go to parent directory")
 	sections = append(sections, "")
 
+	// History
+	sections = append(sections, m.theme.TitleStyle.Render("History:"))
+	sections = append(sections, "  H/L        Back / forward in visited directories")
+	sections = append(sections, "  Ctrl+O     Show jump list")
+	sections = append(sections, "  m<letter>  Bookmark current directory")
+	sections = append(sections, "  '<letter>  Jump to bookmark")
+	sections = append(sections, "  Ctrl+P     Fuzzy find across all mounts")
+	sections = append(sections, "  Ctrl+F     Search file contents below current directory")
+	sections = append(sections, "  S          Show search results")
+	sections = append(sections, "  U          Show disk usage below current directory")
+	sections = append(sections, "")
+
 	// File Operations
 	sections = append(sections, m.theme.TitleStyle.Render("File Operations:"))
-	sections = append(sections, "  n          Create new file")
-	sections = append(sections, "  N          Create new directory")
+	sections = append(sections, "  n          Create new file (without active filter)")
+	sections = append(sections, "  N          Create new directory (without active filter)")
 	sections = append(sections, "  d/Del      Delete selected item")
 	sections = append(sections, "  r          Rename selected item")
-	sections = append(sections, "  y          Copy path to clipboard")
+	sections = append(sections, "  y          Yank (copy) to clipboard")
+	sections = append(sections, "  x          Cut to clipboard")
+	sections = append(sections, "  P          Paste clipboard into current directory")
+	sections = append(sections, "  c/F5       Copy to other pane's directory")
+	sections = append(sections, "  M/F6       Move to other pane's directory")
+	sections = append(sections, "  E          Export to a host directory")
+	sections = append(sections, "  I          Import host path into current directory")
+	sections = append(sections, "")
+
+	// Filter
+	sections = append(sections, m.theme.TitleStyle.Render("Filter:"))
+	sections = append(sections, "  /          Filter entries (substring, glob or re:regex)")
+	sections = append(sections, "  n / N      Next / previous match while filtering")
+	sections = append(sections, "  Esc        Clear filter")
+	sections = append(sections, "")
+
+	// Sorting
+	sections = append(sections, m.theme.TitleStyle.Render("Sorting:"))
+	sections = append(sections, "  o          Cycle sort mode (name, size, mtime, type, extension)")
+	sections = append(sections, "  O          Reverse sort order")
+	sections = append(sections, "  D          Toggle directories first")
+	sections = append(sections, "")
+
+	// Selection
+	sections = append(sections, m.theme.TitleStyle.Render("Selection:"))
+	sections = append(sections, "  Space      Mark / unmark entry")
+	sections = append(sections, "  v          Start / end range selection")
+	sections = append(sections, "  Ctrl+A     Select all")
+	sections = append(sections, "  *          Invert selection")
+	sections = append(sections, "  Esc        Clear selection")
 	sections = append(sections, "")
 
 	// View
 	sections = append(sections, m.theme.TitleStyle.Render("View:"))
 	sections = append(sections, "  p          Toggle preview pane")
+	sections = append(sections, "  +/-        Expand / collapse nested JSON and YAML in the preview")
+	sections = append(sections, "  R          Toggle rendered / raw Markdown and data previews")
+	sections = append(sections, "  i          Toggle long listing with size, mtime, mode and type")
+	sections = append(sections, "  T          Toggle tree view (enter expands, backspace collapses)")
+	sections = append(sections, "  w          Toggle dual-pane layout")
+	sections = append(sections, "  Tab        Switch active pane")
+	sections = append(sections, "")
+
+	// Tabs
+	sections = append(sections, m.theme.TitleStyle.Render("Tabs:"))
+	sections = append(sections, "  t          Open new tab at current directory")
+	sections = append(sections, "  Ctrl+W     Close current tab")
+	sections = append(sections, "  ]/[        Next / previous tab")
+	sections = append(sections, "  }/{        Move tab right / left")
 	sections = append(sections, "  Ctrl+R     Refresh current directory")
+	sections = append(sections, "  J          Show background jobs (Ctrl+X cancels)")
 	sections = append(sections, "")
 
 	// Terminal
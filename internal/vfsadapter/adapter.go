@@ -0,0 +1,280 @@
+package vfsadapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/data"
+	"github.com/mwantia/vfsh/internal/transfer"
+)
+
+// Adapter wraps VirtualFileSystem operations for the TUI, the shell and the CLI commands
+type Adapter struct {
+	vfs      vfs.VirtualFileSystem
+	ctx      context.Context
+	archives *archiveCache // Indexes of archives browsed as directories
+	sniffs   *sniffCache   // File types sniffed by earlier listings
+}
+
+// New creates a new adapter for VFS operations
+func New(ctx context.Context, fs vfs.VirtualFileSystem) *Adapter {
+	return &Adapter{
+		vfs:      fs,
+		ctx:      ctx,
+		archives: newArchiveCache(),
+		sniffs:   newSniffCache(),
+	}
+}
+
+// WithContext returns a copy of the adapter that uses ctx for all operations.
+// Canceling ctx aborts long-running operations like Copy.
+func (a *Adapter) WithContext(ctx context.Context) *Adapter {
+	return &Adapter{
+		vfs:      a.vfs,
+		ctx:      ctx,
+		archives: a.archives,
+		sniffs:   a.sniffs,
+	}
+}
+
+// Context returns the context the adapter uses for all operations
+func (a *Adapter) Context() context.Context {
+	return a.ctx
+}
+
+// ListDirectory returns entries in the specified directory
+func (a *Adapter) ListDirectory(path string) ([]*Entry, error) {
+	// Archives and the directories within them are listed from their index
+	if archive, member, ok := a.splitArchivePath(path); ok {
+		return a.listArchive(archive, member)
+	}
+
+	metas, err := a.vfs.ReadDirectory(a.ctx, path)
+	if err != nil {
+		// Special case: if root directory read fails, it might not exist as an entry
+		// Try to infer children by attempting to stat known common directories
+		return nil, err
+	}
+
+	// VFS already returns only entries for this directory
+	// Just convert them to Entry structs
+	entries := make([]*Entry, 0, len(metas))
+
+	for _, meta := range metas {
+		// The key is the entry name relative to the current directory
+		name := meta.Key
+
+		// Build the full absolute path for VFS operations
+		fullPath := filepath.Join(path, name)
+
+		entry := &Entry{
+			Name:     name,
+			Path:     fullPath,
+			Size:     meta.Size,
+			Mode:     meta.Mode,
+			ModTime:  meta.ModifyTime,
+			IsDir:    meta.Mode.IsDir(),
+			MimeType: meta.ContentType,
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Stat returns information about a file or directory
+func (a *Adapter) Stat(path string) (*Entry, error) {
+	if archive, member, ok := a.splitArchivePath(path); ok && member != "" {
+		return a.statArchive(archive, member)
+	}
+
+	meta, err := a.vfs.StatMetadata(a.ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		Name:     filepath.Base(meta.Key),
+		Path:     path,
+		Size:     meta.Size,
+		Mode:     meta.Mode,
+		ModTime:  meta.ModifyTime,
+		IsDir:    meta.Mode.IsDir(),
+		MimeType: meta.ContentType,
+	}
+
+	return entry, nil
+}
+
+// ReadFileContent reads the content of a file for preview
+func (a *Adapter) ReadFileContent(path string, maxBytes int64) (string, error) {
+	if a.inArchive(path) {
+		content, err := a.readHead(path, maxBytes)
+		if err != nil {
+			return "", err
+		}
+		return sanitizeContent(string(content)), nil
+	}
+
+	// Get file info first to check size
+	meta, err := a.vfs.StatMetadata(a.ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	if meta.Mode.IsDir() {
+		return "", data.ErrIsDirectory
+	}
+
+	// Limit read size
+	readSize := meta.Size
+	if readSize > maxBytes {
+		readSize = maxBytes
+	}
+
+	if readSize == 0 {
+		return "", nil
+	}
+
+	// Read file content
+	content, err := a.vfs.ReadFile(a.ctx, path, 0, readSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Convert to string, replacing non-printable characters
+	return sanitizeContent(string(content)), nil
+}
+
+// ReadText reads up to maxBytes of a file and reports whether they are text
+func (a *Adapter) ReadText(path string, maxBytes int) (string, bool, error) {
+	file, err := a.openRead(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer file.Close()
+
+	// Read up to maxBytes
+	buf := make([]byte, maxBytes)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", false, err
+	}
+
+	// A character cut off by the limit does not make the file binary
+	buf = trimPartialRune(buf[:n])
+
+	// Validate it's actually text
+	return string(buf), isValidUTF8(buf), nil
+}
+
+// CreateDirectory creates a new directory
+func (a *Adapter) CreateDirectory(path string) error {
+	if a.inArchive(path) {
+		return errArchiveReadOnly
+	}
+	return a.vfs.CreateDirectory(a.ctx, path)
+}
+
+// CreateFile creates a new empty file
+func (a *Adapter) CreateFile(path string) error {
+	if a.inArchive(path) {
+		return errArchiveReadOnly
+	}
+
+	file, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeWrite|data.AccessModeCreate|data.AccessModeExcl)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return nil
+}
+
+// Delete removes a file or directory
+func (a *Adapter) Delete(path string, isDir bool) error {
+	if a.inArchive(path) {
+		return errArchiveReadOnly
+	}
+	if isDir {
+		return a.vfs.RemoveDirectory(a.ctx, path, false)
+	}
+	return a.vfs.UnlinkFile(a.ctx, path)
+}
+
+// DeleteRecursive removes a directory and all its contents
+func (a *Adapter) DeleteRecursive(path string) error {
+	if a.inArchive(path) {
+		return errArchiveReadOnly
+	}
+	return a.vfs.RemoveDirectory(a.ctx, path, true)
+}
+
+// Exists checks if a path exists
+func (a *Adapter) Exists(path string) bool {
+	if archive, member, ok := a.splitArchivePath(path); ok && member != "" {
+		_, err := a.statArchive(archive, member)
+		return err == nil
+	}
+
+	exists, _ := a.vfs.LookupMetadata(a.ctx, path)
+	return exists
+}
+
+// sanitizeContent replaces non-printable characters for display
+func sanitizeContent(content string) string {
+	var builder strings.Builder
+	for _, r := range content {
+		if r == '\n' || r == '\r' || r == '\t' || (r >= 32 && r < 127) || r >= 160 {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune('�')
+		}
+	}
+	return builder.String()
+}
+
+// WriteFile writes content to a file
+func (a *Adapter) WriteFile(path string, content []byte) error {
+	if a.inArchive(path) {
+		return errArchiveReadOnly
+	}
+
+	file, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeWrite|data.AccessModeCreate|data.AccessModeTrunc)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(content)
+	return err
+}
+
+// Export copies a file or directory tree from the vfs into a host directory, reporting progress to fn
+func (a *Adapter) Export(path, hostDir string, fn ProgressFunc) (*transfer.Summary, error) {
+	if a.inArchive(path) {
+		return nil, fmt.Errorf("cannot export from inside an archive, copy '%s' into the VFS first", filepath.Base(path))
+	}
+	return transfer.Get(a.ctx, a.vfs, path, hostDir, transfer.Options{OnProgress: fn})
+}
+
+// Import copies a host file or directory tree into a vfs directory, reporting progress to fn
+func (a *Adapter) Import(hostPath, dir string, fn ProgressFunc) (*transfer.Summary, error) {
+	if a.inArchive(dir) {
+		return nil, errArchiveReadOnly
+	}
+	return transfer.Put(a.ctx, a.vfs, hostPath, dir, transfer.Options{OnProgress: fn})
+}
+
+// Execute runs a vfs command and writes its output to w
+func (a *Adapter) Execute(w io.Writer, args ...string) (int, error) {
+	return a.vfs.Execute(a.ctx, w, args...)
+}
+
+// StreamFile opens a file, or a member of an archive, for streaming read operations
+func (a *Adapter) StreamFile(path string) (io.ReadCloser, error) {
+	return a.openRead(path)
+}
@@ -0,0 +1,771 @@
+package vfsadapter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mwantia/vfs/data"
+)
+
+// maxCachedArchives limits how many archive indexes are kept in memory
+const maxCachedArchives = 16
+
+// errArchiveReadOnly is returned for any change to a path inside an archive
+var errArchiveReadOnly = errors.New("archives are read-only, copy members out to change them")
+
+// archiveFormat is how the members of an archive are stored
+type archiveFormat int
+
+const (
+	archiveNone archiveFormat = iota
+	archiveZip
+	archiveTar
+	archiveTarGzip
+	archiveTarBzip2
+	archiveGzip  // A single gzip compressed file
+	archiveBzip2 // A single bzip2 compressed file
+)
+
+// String returns the usual name of the format
+func (f archiveFormat) String() string {
+	switch f {
+	case archiveZip:
+		return "zip"
+	case archiveTar:
+		return "tar"
+	case archiveTarGzip:
+		return "tar.gz"
+	case archiveTarBzip2:
+		return "tar.bz2"
+	case archiveGzip:
+		return "gzip"
+	case archiveBzip2:
+		return "bzip2"
+	default:
+		return "unknown"
+	}
+}
+
+// compressed reports whether the archive is one compressed stream,
+// so members can only be reached by decompressing everything before them
+func (f archiveFormat) compressed() bool {
+	return f != archiveZip && f != archiveTar
+}
+
+// archiveFormatOf returns how a file of type t is read as archive. Whether a compressed
+// file holds a tar archive is guessed from its name here and checked when it is opened.
+func archiveFormatOf(t FileType, name string) archiveFormat {
+	lower := strings.ToLower(name)
+	switch t.MimeType {
+	case "application/zip":
+		return archiveZip
+	case "application/x-tar":
+		return archiveTar
+	case "application/gzip":
+		if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+			return archiveTarGzip
+		}
+		return archiveGzip
+	case "application/x-bzip2":
+		if strings.HasSuffix(lower, ".tar.bz2") || strings.HasSuffix(lower, ".tbz2") {
+			return archiveTarBzip2
+		}
+		return archiveBzip2
+	default:
+		return archiveNone
+	}
+}
+
+// isArchiveName reports whether name has the extension of an archive that can be browsed.
+// Paths are only resolved into archives at such names, so other files are never read for it.
+func isArchiveName(name string) bool {
+	t, ok := detectExtension(name)
+	return ok && archiveFormatOf(t, name) != archiveNone
+}
+
+// InArchive reports whether the entry is a member of an archive, which is read-only
+func (e *Entry) InArchive() bool {
+	return e.inArchive
+}
+
+// IsBrowsableArchive reports whether the entry can be opened as a read-only directory
+func (e *Entry) IsBrowsableArchive() bool {
+	return !e.IsDir && !e.inArchive && isArchiveName(e.Name) && e.FileType().Kind == KindArchive
+}
+
+// archiveMember is a file or directory stored in an archive
+type archiveMember struct {
+	Name    string // Slash-separated path within the archive
+	Size    int64
+	Mode    data.FileMode
+	ModTime time.Time
+	IsDir   bool
+
+	offset   int64 // Start of the content in an uncompressed tar archive
+	position int   // Position among the stored entries, to find a member stored twice again
+}
+
+// archiveIndex lists the members of an archive
+type archiveIndex struct {
+	format   archiveFormat
+	size     int64     // Size of the archive file, to notice changes
+	modTime  time.Time // Modification time of the archive file, to notice changes
+	members  map[string]*archiveMember
+	children map[string][]*archiveMember // Members by directory, "" is the root
+	order    []*archiveMember            // Members in the order they are stored
+
+	files    int
+	dirs     int
+	unpacked int64 // Total size of all files
+}
+
+// newArchiveIndex creates an empty index for an archive file
+func newArchiveIndex(format archiveFormat, size int64, modTime time.Time) *archiveIndex {
+	return &archiveIndex{
+		format:   format,
+		size:     size,
+		modTime:  modTime,
+		members:  make(map[string]*archiveMember),
+		children: make(map[string][]*archiveMember),
+	}
+}
+
+// cleanMemberName normalizes a stored name relative to the archive root. Elements that
+// would climb above the root are dropped, so "../escape" becomes "escape" and stays inside.
+// Names that clean to the root itself, such as "/" or "..", return "".
+func cleanMemberName(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	return strings.TrimPrefix(name, "/")
+}
+
+// add records a member and the directories leading to it that are not stored themselves.
+// A member stored twice, which tar allows, keeps the attributes of the later one.
+func (x *archiveIndex) add(member archiveMember) *archiveMember {
+	member.Name = cleanMemberName(member.Name)
+	if member.Name == "" {
+		return nil
+	}
+
+	if existing, ok := x.members[member.Name]; ok {
+		// An implied directory gets its attributes once the directory itself is stored
+		if existing.IsDir != member.IsDir {
+			x.count(existing, -1)
+			x.count(&member, 1)
+		} else if !existing.IsDir {
+			x.unpacked += member.Size - existing.Size
+		}
+		*existing = member
+		return existing
+	}
+
+	parent := path.Dir(member.Name)
+	if parent == "." {
+		parent = ""
+	} else if _, ok := x.members[parent]; !ok {
+		x.add(archiveMember{Name: parent, Mode: data.FileMode(fs.ModeDir | 0o755), ModTime: member.ModTime, IsDir: true})
+	}
+
+	stored := &member
+	x.members[member.Name] = stored
+	x.children[parent] = append(x.children[parent], stored)
+	x.order = append(x.order, stored)
+	x.count(stored, 1)
+	return stored
+}
+
+// count updates the totals for a member being added (1) or removed (-1)
+func (x *archiveIndex) count(member *archiveMember, delta int) {
+	if member.IsDir {
+		x.dirs += delta
+		return
+	}
+	x.files += delta
+	x.unpacked += int64(delta) * member.Size
+}
+
+// archiveCache keeps the indexes of recently opened archives, shared by all copies of an adapter
+type archiveCache struct {
+	mu      sync.Mutex
+	indexes map[string]*archiveIndex
+}
+
+// newArchiveCache creates an empty cache
+func newArchiveCache() *archiveCache {
+	return &archiveCache{indexes: make(map[string]*archiveIndex)}
+}
+
+// get returns the index of the archive at path if it has not changed since
+func (c *archiveCache) get(path string, size int64, modTime time.Time) *archiveIndex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, ok := c.indexes[path]
+	if !ok || index.size != size || !index.modTime.Equal(modTime) {
+		return nil
+	}
+	return index
+}
+
+// put remembers the index of the archive at path, dropping an older one if the cache is full
+func (c *archiveCache) put(path string, index *archiveIndex) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.indexes[path]; !ok && len(c.indexes) >= maxCachedArchives {
+		for key := range c.indexes {
+			delete(c.indexes, key)
+			break
+		}
+	}
+	c.indexes[path] = index
+}
+
+// vfsReaderAt reads a file with range reads, so the directory of a zip archive
+// or a member of a tar archive is read without reading the whole archive
+type vfsReaderAt struct {
+	adapter *Adapter
+	path    string
+	size    int64
+}
+
+// ReadAt implements io.ReaderAt
+func (r *vfsReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	size := int64(len(p))
+	if size > r.size-off {
+		size = r.size - off
+	}
+	content, err := r.adapter.vfs.ReadFile(r.adapter.ctx, r.path, off, size)
+	n := copy(p, content)
+	if err != nil {
+		return n, err
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readCloser combines the reader of a member with the file it is read from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// splitArchivePath finds the archive a path leads into. It returns the path of the archive
+// and the member within it, which is empty for the archive itself. ok is false if the path
+// does not lead through an archive.
+func (a *Adapter) splitArchivePath(p string) (archive, member string, ok bool) {
+	p = filepath.Clean(p)
+	parts := strings.Split(p, "/")
+
+	for i, part := range parts {
+		if !isArchiveName(part) {
+			continue
+		}
+
+		prefix := strings.Join(parts[:i+1], "/")
+		meta, err := a.vfs.StatMetadata(a.ctx, prefix)
+		if err != nil || meta.Mode.IsDir() {
+			continue
+		}
+		return prefix, strings.Join(parts[i+1:], "/"), true
+	}
+
+	return "", "", false
+}
+
+// inArchive reports whether path is a member of an archive
+func (a *Adapter) inArchive(path string) bool {
+	_, member, ok := a.splitArchivePath(path)
+	return ok && member != ""
+}
+
+// archiveIndex returns the members of the archive at path, reading them once per change of the file
+func (a *Adapter) archiveIndex(path string) (*archiveIndex, error) {
+	meta, err := a.vfs.StatMetadata(a.ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Mode.IsDir() {
+		return nil, data.ErrIsDirectory
+	}
+	if index := a.archives.get(path, meta.Size, meta.ModifyTime); index != nil {
+		return index, nil
+	}
+
+	// The content decides the format, so misnamed archives are still read correctly
+	var head []byte
+	if size := meta.Size; size > 0 {
+		if size > sniffSize {
+			size = sniffSize
+		}
+		head, err = a.vfs.ReadFile(a.ctx, path, 0, size)
+		if err != nil {
+			return nil, err
+		}
+	}
+	format := archiveFormatOf(DetectFileType(filepath.Base(path), meta.ContentType, head), filepath.Base(path))
+
+	index := newArchiveIndex(format, meta.Size, meta.ModifyTime)
+	switch format {
+	case archiveZip:
+		err = a.indexZip(path, index)
+	case archiveTar:
+		err = a.indexTar(path, index)
+	case archiveNone:
+		err = fmt.Errorf("%s is not a supported archive", filepath.Base(path))
+	default:
+		err = a.indexCompressed(path, index)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s archive: %w", format, err)
+	}
+
+	a.archives.put(path, index)
+	return index, nil
+}
+
+// indexZip reads the central directory of a zip archive
+func (a *Adapter) indexZip(path string, index *archiveIndex) error {
+	reader, err := zip.NewReader(&vfsReaderAt{adapter: a, path: path, size: index.size}, index.size)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range reader.File {
+		stored := index.add(archiveMember{
+			Name:    f.Name,
+			Size:    int64(f.UncompressedSize64),
+			Mode:    data.FileMode(f.Mode()),
+			ModTime: f.Modified,
+			IsDir:   f.FileInfo().IsDir(),
+		})
+		if stored != nil {
+			stored.position = i
+		}
+	}
+	return nil
+}
+
+// indexTar reads the headers of an uncompressed tar archive, skipping over the content
+// and remembering where each file starts so it can be read with a single range read
+func (a *Adapter) indexTar(path string, index *archiveIndex) error {
+	section := io.NewSectionReader(&vfsReaderAt{adapter: a, path: path, size: index.size}, 0, index.size)
+	reader := tar.NewReader(section)
+
+	return a.scanTar(reader, index, func() int64 {
+		offset, _ := section.Seek(0, io.SeekCurrent)
+		return offset
+	})
+}
+
+// scanTar adds the members of a tar archive to index. offset, if set,
+// returns the position of the content of the current member.
+func (a *Adapter) scanTar(reader *tar.Reader, index *archiveIndex, offset func() int64) error {
+	for position := 0; ; position++ {
+		if err := a.ctx.Err(); err != nil {
+			return err
+		}
+
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		member := archiveMember{
+			Name:    header.Name,
+			Size:    header.Size,
+			Mode:    data.FileMode(header.FileInfo().Mode()),
+			ModTime: header.ModTime,
+			IsDir:   header.Typeflag == tar.TypeDir,
+		}
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+		default:
+			// Links, devices and sparse files have no content of their own to read
+			continue
+		}
+
+		stored := index.add(member)
+		if stored == nil {
+			continue
+		}
+		stored.position = position
+		if offset != nil {
+			stored.offset = offset()
+		}
+	}
+}
+
+// openCompressed opens the decompressed stream of a gzip or bzip2 file.
+// tarStream reports whether the stream holds a tar archive.
+func (a *Adapter) openCompressed(path string, format archiveFormat) (stream *bufio.Reader, file io.Closer, tarStream bool, err error) {
+	f, err := a.vfs.OpenFile(a.ctx, path, data.AccessModeRead)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var decompressed io.Reader
+	switch format {
+	case archiveGzip, archiveTarGzip:
+		decompressed, err = gzip.NewReader(bufio.NewReader(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, false, err
+		}
+	default:
+		decompressed = bzip2.NewReader(bufio.NewReader(f))
+	}
+
+	stream = bufio.NewReaderSize(decompressed, copyBufferSize)
+	head, _ := stream.Peek(sniffSize)
+	tarStream = len(head) >= 262 && string(head[257:262]) == "ustar"
+	return stream, f, tarStream, nil
+}
+
+// indexCompressed reads a compressed tar archive or a single compressed file
+func (a *Adapter) indexCompressed(path string, index *archiveIndex) error {
+	stream, file, tarStream, err := a.openCompressed(path, index.format)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if tarStream {
+		if index.format == archiveGzip {
+			index.format = archiveTarGzip
+		} else if index.format == archiveBzip2 {
+			index.format = archiveTarBzip2
+		}
+		return a.scanTar(tar.NewReader(stream), index, nil)
+	}
+
+	if index.format == archiveTarGzip {
+		index.format = archiveGzip
+	} else if index.format == archiveTarBzip2 {
+		index.format = archiveBzip2
+	}
+
+	member := archiveMember{
+		Name:    compressedMemberName(filepath.Base(path)),
+		Mode:    data.FileMode(0o644),
+		ModTime: index.modTime,
+	}
+
+	// gzip stores the size modulo 4GB at its end, bzip2 has to be decompressed to count
+	if index.format == archiveGzip && index.size >= 4 {
+		trailer, err := a.vfs.ReadFile(a.ctx, path, index.size-4, 4)
+		if err != nil {
+			return err
+		}
+		member.Size = int64(binary.LittleEndian.Uint32(trailer))
+	} else {
+		member.Size, err = io.Copy(io.Discard, readerWithContext{adapter: a, reader: stream})
+		if err != nil {
+			return err
+		}
+	}
+
+	index.add(member)
+	return nil
+}
+
+// compressedMemberName returns the name of the file inside a single compressed file
+func compressedMemberName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tgz"):
+		return name[:len(name)-4] + ".tar"
+	case strings.HasSuffix(lower, ".gz"):
+		return name[:len(name)-3]
+	case strings.HasSuffix(lower, ".tbz2"):
+		return name[:len(name)-5] + ".tar"
+	case strings.HasSuffix(lower, ".bz2"):
+		return name[:len(name)-4]
+	default:
+		return name
+	}
+}
+
+// readerWithContext stops reading once the context of the adapter is canceled
+type readerWithContext struct {
+	adapter *Adapter
+	reader  io.Reader
+}
+
+// Read implements io.Reader
+func (r readerWithContext) Read(p []byte) (int, error) {
+	if err := r.adapter.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// listArchive returns the members of a directory inside an archive as entries
+func (a *Adapter) listArchive(archive, dir string) ([]*Entry, error) {
+	index, err := a.archiveIndex(archive)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		member, ok := index.members[dir]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", dir, fs.ErrNotExist)
+		}
+		if !member.IsDir {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+
+	children := index.children[dir]
+	entries := make([]*Entry, 0, len(children))
+	for _, member := range children {
+		entries = append(entries, memberEntry(archive, member))
+	}
+	return entries, nil
+}
+
+// statArchive returns the entry of a member of an archive
+func (a *Adapter) statArchive(archive, name string) (*Entry, error) {
+	index, err := a.archiveIndex(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok := index.members[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return memberEntry(archive, member), nil
+}
+
+// memberEntry converts a member into an entry whose path leads through the archive
+func memberEntry(archive string, member *archiveMember) *Entry {
+	return &Entry{
+		Name:      path.Base(member.Name),
+		Path:      archive + "/" + member.Name,
+		Size:      member.Size,
+		Mode:      member.Mode,
+		ModTime:   member.ModTime,
+		IsDir:     member.IsDir,
+		inArchive: true,
+	}
+}
+
+// openArchiveMember opens the content of a file inside an archive
+func (a *Adapter) openArchiveMember(archive, name string) (io.ReadCloser, error) {
+	index, err := a.archiveIndex(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	member, ok := index.members[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	if member.IsDir {
+		return nil, data.ErrIsDirectory
+	}
+
+	// Members stored twice are opened at the position of the later one, as listed
+	source := &vfsReaderAt{adapter: a, path: archive, size: index.size}
+	switch index.format {
+	case archiveZip:
+		reader, err := zip.NewReader(source, index.size)
+		if err != nil {
+			return nil, err
+		}
+		if member.position >= len(reader.File) || cleanMemberName(reader.File[member.position].Name) != name {
+			return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+		}
+		return reader.File[member.position].Open()
+
+	case archiveTar:
+		return io.NopCloser(io.NewSectionReader(source, member.offset, member.Size)), nil
+
+	case archiveGzip, archiveBzip2:
+		stream, file, _, err := a.openCompressed(archive, index.format)
+		if err != nil {
+			return nil, err
+		}
+		return readCloser{Reader: stream, Closer: file}, nil
+
+	default:
+		// Members of a compressed tar archive are found by decompressing everything before them
+		stream, file, _, err := a.openCompressed(archive, index.format)
+		if err != nil {
+			return nil, err
+		}
+		reader := tar.NewReader(readerWithContext{adapter: a, reader: stream})
+		for position := 0; ; position++ {
+			header, err := reader.Next()
+			if err != nil {
+				file.Close()
+				if errors.Is(err, io.EOF) {
+					return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+				}
+				return nil, err
+			}
+			if position == member.position && cleanMemberName(header.Name) == name {
+				return readCloser{Reader: reader, Closer: file}, nil
+			}
+		}
+	}
+}
+
+// extractTar copies the directory dir of a compressed tar archive to dst in a single pass.
+// Opening every member on its own would decompress the archive up to it each time.
+func (op *copyOperation) extractTar(archive, dir, dst string) error {
+	a := op.adapter
+	index, err := a.archiveIndex(archive)
+	if err != nil {
+		return err
+	}
+
+	// Directories are created up front, as files are written in the order they are stored
+	if err := op.createMemberDirectories(index, dir, dst); err != nil {
+		return err
+	}
+
+	stream, file, _, err := a.openCompressed(archive, index.format)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(readerWithContext{adapter: a, reader: stream})
+	for position := 0; ; position++ {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := cleanMemberName(header.Name)
+		member, ok := index.members[name]
+		if !ok || member.IsDir || member.position != position || !strings.HasPrefix(name, dir+"/") {
+			continue
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(name, dir+"/")))
+		if err := op.writeFile(archive+"/"+name, target, reader); err != nil {
+			return err
+		}
+	}
+}
+
+// createMemberDirectories recreates dir of an archive and all directories below it at dst
+func (op *copyOperation) createMemberDirectories(index *archiveIndex, dir, dst string) error {
+	if !op.adapter.Exists(dst) {
+		if err := op.adapter.CreateDirectory(dst); err != nil {
+			return fmt.Errorf("failed to create directory '%s': %w", dst, err)
+		}
+	}
+
+	for _, child := range index.children[dir] {
+		if !child.IsDir {
+			continue
+		}
+		if err := op.createMemberDirectories(index, child.Name, filepath.Join(dst, path.Base(child.Name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openRead opens a file, or a member if the path leads into an archive, for reading
+func (a *Adapter) openRead(path string) (io.ReadCloser, error) {
+	if archive, member, ok := a.splitArchivePath(path); ok && member != "" {
+		return a.openArchiveMember(archive, member)
+	}
+	return a.vfs.OpenFile(a.ctx, path, data.AccessModeRead)
+}
+
+// readHead reads up to size bytes from the start of a file or archive member
+func (a *Adapter) readHead(path string, size int64) ([]byte, error) {
+	archive, member, ok := a.splitArchivePath(path)
+	if !ok || member == "" {
+		return a.vfs.ReadFile(a.ctx, path, 0, size)
+	}
+
+	reader, err := a.openArchiveMember(archive, member)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	head := make([]byte, size)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// DescribeArchive lists the members of an archive with their sizes.
+// The listing is fitted into width x height cells, unless they are zero.
+func (a *Adapter) DescribeArchive(path string, width, height int) (string, error) {
+	index, err := a.archiveIndex(path)
+	if err != nil {
+		return "", err
+	}
+
+	var preview strings.Builder
+	fmt.Fprintf(&preview, "Archive: %s, %d files, %d directories\n", index.format, index.files, index.dirs)
+	fmt.Fprintf(&preview, "Size: %s unpacked, %s packed\n", FormatSize(index.unpacked), FormatSize(index.size))
+	if index.format.compressed() && len(index.order) > 1 {
+		preview.WriteString("Members are read by decompressing the archive up to them\n")
+	}
+	preview.WriteString("\n")
+
+	// Leave room for the header and the line counting the hidden members
+	available := len(index.order)
+	if height > 0 {
+		available = max(height-6, 1)
+	}
+
+	for i, member := range index.order {
+		if i == available {
+			fmt.Fprintf(&preview, "... and %d more\n", len(index.order)-i)
+			break
+		}
+
+		size := FormatSize(member.Size)
+		name := member.Name
+		if member.IsDir {
+			size = "<DIR>"
+			name += "/"
+		}
+		line := fmt.Sprintf("%10s  %s", size, name)
+		if width > 0 && utf8.RuneCountInString(line) > width {
+			line = string([]rune(line)[:width])
+		}
+		preview.WriteString(line)
+		preview.WriteString("\n")
+	}
+
+	return strings.TrimRight(preview.String(), "\n"), nil
+}
@@ -0,0 +1,132 @@
+package vfsadapter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCleanMemberName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"file.txt", "file.txt"},
+		{"dir/", "dir"},
+		{"./dir/file", "dir/file"},
+		{"/abs/file", "abs/file"},
+		{"dir//file", "dir/file"},
+		{`dir\file`, "dir/file"},
+		{"../escape", "escape"},
+		{"dir/../../file", "file"},
+		{"..", ""},
+		{".", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanMemberName(tt.name); got != tt.want {
+				t.Fatalf("cleanMemberName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveIndexAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []archiveMember
+		files    int
+		dirs     int
+		unpacked int64
+		root     []string // Names of the root children in order
+	}{
+		{
+			name:     "implied directories",
+			members:  []archiveMember{{Name: "a/b/file", Size: 10}},
+			files:    1,
+			dirs:     2,
+			unpacked: 10,
+			root:     []string{"a"},
+		},
+		{
+			name:     "stored directory after implied",
+			members:  []archiveMember{{Name: "a/file", Size: 5}, {Name: "a/", IsDir: true}},
+			files:    1,
+			dirs:     1,
+			unpacked: 5,
+			root:     []string{"a"},
+		},
+		{
+			name:     "duplicate keeps later size",
+			members:  []archiveMember{{Name: "file", Size: 5}, {Name: "file", Size: 7}},
+			files:    1,
+			unpacked: 7,
+			root:     []string{"file"},
+		},
+		{
+			name:     "file replaced by directory",
+			members:  []archiveMember{{Name: "x", Size: 3}, {Name: "x", IsDir: true}},
+			dirs:     1,
+			unpacked: 0,
+			root:     []string{"x"},
+		},
+		{
+			name:    "escaping names are dropped",
+			members: []archiveMember{{Name: "..", Size: 1}, {Name: "/", IsDir: true}},
+		},
+		{
+			name:     "stored order",
+			members:  []archiveMember{{Name: "b", Size: 1}, {Name: "a", Size: 2}, {Name: "./c", Size: 3}},
+			files:    3,
+			unpacked: 6,
+			root:     []string{"b", "a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index := newArchiveIndex(archiveTar, 0, time.Time{})
+			for _, member := range tt.members {
+				index.add(member)
+			}
+
+			if index.files != tt.files || index.dirs != tt.dirs || index.unpacked != tt.unpacked {
+				t.Fatalf("got %d files, %d dirs, %d bytes, want %d, %d, %d",
+					index.files, index.dirs, index.unpacked, tt.files, tt.dirs, tt.unpacked)
+			}
+
+			var root []string
+			for _, member := range index.children[""] {
+				root = append(root, member.Name)
+			}
+			if len(root) != len(tt.root) {
+				t.Fatalf("root = %v, want %v", root, tt.root)
+			}
+			for i := range root {
+				if root[i] != tt.root[i] {
+					t.Fatalf("root = %v, want %v", root, tt.root)
+				}
+			}
+		})
+	}
+}
+
+func TestArchiveIndexAddDuplicateReturnsExisting(t *testing.T) {
+	index := newArchiveIndex(archiveTar, 0, time.Time{})
+	first := index.add(archiveMember{Name: "dir/file", Size: 1})
+	first.position = 1
+
+	second := index.add(archiveMember{Name: "dir/file", Size: 2})
+	second.position = 4
+
+	if first != second {
+		t.Fatalf("duplicate member was stored twice")
+	}
+	if got := index.members["dir/file"]; got.Size != 2 || got.position != 4 {
+		t.Fatalf("member has size %d at position %d, want the later one", got.Size, got.position)
+	}
+	if n := len(index.children["dir"]); n != 1 {
+		t.Fatalf("directory lists %d children, want 1", n)
+	}
+}
@@ -0,0 +1,209 @@
+package vfsadapter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mwantia/vfs/data"
+	"github.com/mwantia/vfsh/internal/transfer"
+)
+
+// copyBufferSize is the chunk size used when streaming file contents
+const copyBufferSize = 256 * 1024
+
+// CopyProgress describes the state of a running copy operation.
+// Copies within the vfs and host transfers report the same progress.
+type CopyProgress = transfer.Progress
+
+// ProgressFunc receives progress updates while copying
+type ProgressFunc func(CopyProgress)
+
+// copyOperation holds the state shared across a recursive copy
+type copyOperation struct {
+	adapter  *Adapter
+	buffer   []byte
+	progress ProgressFunc
+	state    CopyProgress
+}
+
+// CopyFile copies a file or directory tree from src to dst
+func (a *Adapter) CopyFile(src, dst string) error {
+	return a.Copy(src, dst, nil)
+}
+
+// Copy streams a file or directory tree from src to dst, reporting progress to fn.
+// The copy works across mount boundaries and stops once the adapter's context is canceled.
+func (a *Adapter) Copy(src, dst string, fn ProgressFunc) error {
+	src = filepath.Clean(src)
+	dst = filepath.Clean(dst)
+
+	if src == dst {
+		return fmt.Errorf("source and destination are the same: %s", src)
+	}
+	if strings.HasPrefix(dst, src+"/") {
+		return fmt.Errorf("cannot copy '%s' into itself", src)
+	}
+
+	// Members of archives can be copied out, but nothing can be copied into one
+	if a.inArchive(dst) {
+		return errArchiveReadOnly
+	}
+
+	srcEntry, err := a.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	op := &copyOperation{
+		adapter:  a,
+		buffer:   make([]byte, copyBufferSize),
+		progress: fn,
+	}
+
+	if !srcEntry.IsDir {
+		op.state.TotalFiles = 1
+		op.state.TotalBytes = srcEntry.Size
+		return op.copyFile(src, dst)
+	}
+
+	// Collect totals up front so progress can be reported relative to them
+	if fn != nil {
+		if err := op.measure(src); err != nil {
+			return err
+		}
+	}
+
+	// Compressed tar archives can only be read front to back, so they are extracted in one pass
+	if archive, member, ok := a.splitArchivePath(src); ok && member != "" {
+		if index, err := a.archiveIndex(archive); err == nil && (index.format == archiveTarGzip || index.format == archiveTarBzip2) {
+			return op.extractTar(archive, member, dst)
+		}
+	}
+
+	return op.copyDirectory(src, dst)
+}
+
+// measure sums up the number of files and bytes below path
+func (op *copyOperation) measure(path string) error {
+	entries, err := op.adapter.ListDirectory(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := op.adapter.ctx.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir {
+			if err := op.measure(entry.Path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		op.state.TotalFiles++
+		op.state.TotalBytes += entry.Size
+	}
+
+	return nil
+}
+
+// copyDirectory recreates src at dst and copies all children
+func (op *copyOperation) copyDirectory(src, dst string) error {
+	if !op.adapter.Exists(dst) {
+		if err := op.adapter.CreateDirectory(dst); err != nil {
+			return fmt.Errorf("failed to create directory '%s': %w", dst, err)
+		}
+	}
+
+	entries, err := op.adapter.ListDirectory(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		target := filepath.Join(dst, entry.Name)
+
+		if entry.IsDir {
+			err = op.copyDirectory(entry.Path, target)
+		} else {
+			err = op.copyFile(entry.Path, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyFile streams a single file from src to dst in chunks
+func (op *copyOperation) copyFile(src, dst string) error {
+	srcFile, err := op.adapter.openRead(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	return op.writeFile(src, dst, srcFile)
+}
+
+// writeFile streams the content of src, read from r, into the file dst
+func (op *copyOperation) writeFile(src, dst string, r io.Reader) error {
+	ctx := op.adapter.ctx
+
+	dstFile, err := op.adapter.vfs.OpenFile(ctx, dst, data.AccessModeWrite|data.AccessModeCreate|data.AccessModeTrunc)
+	if err != nil {
+		return err
+	}
+
+	op.state.Path = src
+	err = op.stream(dstFile, r)
+
+	if closeErr := dstFile.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy '%s': %w", src, err)
+	}
+
+	op.state.Files++
+	op.report()
+	return nil
+}
+
+// stream copies from r to w using the shared buffer, checking for cancellation between chunks
+func (op *copyOperation) stream(w io.Writer, r io.Reader) error {
+	for {
+		if err := op.adapter.ctx.Err(); err != nil {
+			return err
+		}
+
+		n, readErr := r.Read(op.buffer)
+		if n > 0 {
+			if _, err := w.Write(op.buffer[:n]); err != nil {
+				return err
+			}
+			op.state.Bytes += int64(n)
+			op.report()
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
+
+// report forwards the current state to the progress callback
+func (op *copyOperation) report() {
+	if op.progress != nil {
+		op.progress(op.state)
+	}
+}
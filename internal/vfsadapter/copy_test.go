@@ -0,0 +1,260 @@
+package vfsadapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mwantia/vfs"
+	"github.com/mwantia/vfs/mount/backend/ephemeral"
+)
+
+// newTestAdapter creates an adapter on an ephemeral root with a second mount at /other.
+// Paths in files that end with a slash are created as directories.
+func newTestAdapter(t *testing.T, files map[string]string) *Adapter {
+	t.Helper()
+
+	ctx := context.Background()
+	fs, err := vfs.NewVirtualFileSystem(vfs.WithLogFile(filepath.Join(t.TempDir(), "vfs.log")), vfs.WithoutTerminalLog())
+	if err != nil {
+		t.Fatalf("failed to setup vfs: %v", err)
+	}
+	t.Cleanup(func() { _ = fs.Shutdown(ctx) })
+
+	for _, path := range []string{"/", "/other"} {
+		if err := fs.Mount(ctx, path, ephemeral.NewEphemeralBackend()); err != nil {
+			t.Fatalf("failed to mount '%s': %v", path, err)
+		}
+	}
+
+	// Sorting creates every directory before its children
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	adapter := New(ctx, fs)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "/") {
+			err = adapter.CreateDirectory(strings.TrimSuffix(path, "/"))
+		} else {
+			err = adapter.WriteFile(path, []byte(files[path]))
+		}
+		if err != nil {
+			t.Fatalf("failed to create '%s': %v", path, err)
+		}
+	}
+
+	return adapter
+}
+
+// readTestFile returns the full content of the file at path
+func readTestFile(t *testing.T, a *Adapter, path string) string {
+	t.Helper()
+
+	reader, err := a.StreamFile(path)
+	if err != nil {
+		t.Fatalf("failed to open '%s': %v", path, err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read '%s': %v", path, err)
+	}
+	return string(content)
+}
+
+func TestCopy(t *testing.T) {
+	large := strings.Repeat("0123456789abcdef", copyBufferSize/16*2+1)
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		src   string
+		dst   string
+		want  map[string]string // Files expected after the copy and their content
+		final CopyProgress      // Progress reported last, without the path
+	}{
+		{
+			name:  "file",
+			files: map[string]string{"/a.txt": "hello"},
+			src:   "/a.txt",
+			dst:   "/b.txt",
+			want:  map[string]string{"/a.txt": "hello", "/b.txt": "hello"},
+			final: CopyProgress{Files: 1, TotalFiles: 1, Bytes: 5, TotalBytes: 5},
+		},
+		{
+			name:  "file across mounts",
+			files: map[string]string{"/a.txt": "hello"},
+			src:   "/a.txt",
+			dst:   "/other/a.txt",
+			want:  map[string]string{"/other/a.txt": "hello"},
+			final: CopyProgress{Files: 1, TotalFiles: 1, Bytes: 5, TotalBytes: 5},
+		},
+		{
+			name:  "file larger than the buffer",
+			files: map[string]string{"/large.bin": large},
+			src:   "/large.bin",
+			dst:   "/copy.bin",
+			want:  map[string]string{"/copy.bin": large},
+			final: CopyProgress{Files: 1, TotalFiles: 1, Bytes: int64(len(large)), TotalBytes: int64(len(large))},
+		},
+		{
+			name: "directory tree",
+			files: map[string]string{
+				"/src/":          "",
+				"/src/a.txt":     "alpha",
+				"/src/sub/":      "",
+				"/src/sub/b.txt": "beta",
+				"/src/sub/empty": "",
+			},
+			src: "/src",
+			dst: "/other/dst",
+			want: map[string]string{
+				"/other/dst/a.txt":     "alpha",
+				"/other/dst/sub/b.txt": "beta",
+				"/other/dst/sub/empty": "",
+			},
+			final: CopyProgress{Files: 3, TotalFiles: 3, Bytes: 9, TotalBytes: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newTestAdapter(t, tt.files)
+
+			var reports []CopyProgress
+			if err := adapter.Copy(tt.src, tt.dst, func(p CopyProgress) { reports = append(reports, p) }); err != nil {
+				t.Fatalf("Copy(%q, %q) = %v, want nil", tt.src, tt.dst, err)
+			}
+
+			for path, want := range tt.want {
+				if got := readTestFile(t, adapter, path); got != want {
+					t.Fatalf("%s has %d bytes after copy, want %d", path, len(got), len(want))
+				}
+			}
+
+			if len(reports) == 0 {
+				t.Fatalf("Copy(%q, %q) reported no progress", tt.src, tt.dst)
+			}
+			final := reports[len(reports)-1]
+			final.Path = ""
+			if final != tt.final {
+				t.Fatalf("final progress = %+v, want %+v", final, tt.final)
+			}
+
+			for i := 1; i < len(reports); i++ {
+				if reports[i].Bytes < reports[i-1].Bytes {
+					t.Fatalf("progress went back from %d to %d bytes", reports[i-1].Bytes, reports[i].Bytes)
+				}
+			}
+		})
+	}
+}
+
+func TestCopyStreamsInChunks(t *testing.T) {
+	content := bytes.Repeat([]byte{'x'}, copyBufferSize*3)
+	adapter := newTestAdapter(t, map[string]string{"/large.bin": string(content)})
+
+	var chunks int
+	err := adapter.Copy("/large.bin", "/copy.bin", func(p CopyProgress) {
+		if p.Files == 0 {
+			chunks++
+		}
+	})
+	if err != nil {
+		t.Fatalf("Copy() = %v, want nil", err)
+	}
+
+	if chunks < 3 {
+		t.Fatalf("got %d progress reports while streaming, want at least 3", chunks)
+	}
+}
+
+func TestCopyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		dst  string
+		want string
+	}{
+		{"same path", "/src", "/src/", "source and destination are the same"},
+		{"into itself", "/src", "/src/sub/copy", "into itself"},
+		{"missing source", "/missing", "/copy", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newTestAdapter(t, map[string]string{"/src/": "", "/src/sub/": ""})
+
+			err := adapter.Copy(tt.src, tt.dst, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Copy(%q, %q) = %v, want error containing %q", tt.src, tt.dst, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyCanceled(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		src    string
+		cancel int // Number of progress reports before the context is canceled, -1 for before the copy
+	}{
+		{
+			name:   "before the copy",
+			files:  map[string]string{"/a.txt": "hello"},
+			src:    "/a.txt",
+			cancel: -1,
+		},
+		{
+			name:   "between chunks",
+			files:  map[string]string{"/large.bin": strings.Repeat("x", copyBufferSize*3)},
+			src:    "/large.bin",
+			cancel: 1,
+		},
+		{
+			name:   "between files",
+			files:  map[string]string{"/src/": "", "/src/a.txt": "alpha", "/src/b.txt": "beta", "/src/c.txt": "gamma"},
+			src:    "/src",
+			cancel: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			adapter := newTestAdapter(t, tt.files).WithContext(ctx)
+			if tt.cancel < 0 {
+				cancel()
+			}
+
+			var last CopyProgress
+			var reports int
+			err := adapter.Copy(tt.src, "/copy", func(p CopyProgress) {
+				last = p
+				reports++
+				if reports == tt.cancel {
+					cancel()
+				}
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Copy() = %v, want %v", err, context.Canceled)
+			}
+
+			if last.TotalFiles > 0 && last.Files >= last.TotalFiles && last.Bytes >= last.TotalBytes {
+				t.Fatalf("copy completed after cancel: %+v", last)
+			}
+		})
+	}
+}
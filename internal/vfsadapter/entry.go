@@ -0,0 +1,70 @@
+package vfsadapter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mwantia/vfs/data"
+)
+
+// Entry represents a file or directory entry
+type Entry struct {
+	Name     string
+	Path     string
+	Size     int64
+	Mode     data.FileMode
+	ModTime  time.Time
+	IsDir    bool
+	MimeType data.ContentType
+
+	fileType  *FileType // Set once the content has been sniffed
+	inArchive bool      // Member of an archive, which is read-only
+}
+
+// DisplayName returns the name with appropriate indicator
+func (e *Entry) DisplayName() string {
+	if e.IsDir {
+		return e.Name + "/"
+	}
+	return e.Name
+}
+
+// DisplaySize returns human-readable size
+func (e *Entry) DisplaySize() string {
+	// Check if it's a mount point first
+	if e.Mode.IsMount() {
+		return "<MNT>"
+	}
+
+	if e.IsDir {
+		return "<DIR>"
+	}
+
+	return FormatSize(e.Size)
+}
+
+// FormatSize returns a human-readable representation of size in bytes
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// DisplayMode returns file permissions as string
+func (e *Entry) DisplayMode() string {
+	return e.Mode.String()
+}
+
+// DisplayModTime returns formatted modification time
+func (e *Entry) DisplayModTime() string {
+	return e.ModTime.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,480 @@
+package vfsadapter
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mwantia/vfs/data"
+)
+
+const (
+	// sniffSize is how many leading bytes are read to recognize the format of a file
+	sniffSize = 512
+	// maxSniffEntries limits how many files of a single listing are sniffed
+	maxSniffEntries = 256
+	// maxCachedSniffs limits how many sniffed types are remembered across listings
+	maxCachedSniffs = 4096
+)
+
+// FileKind is the broad category of a file, used for icons and previews
+type FileKind int
+
+const (
+	KindUnknown FileKind = iota
+	KindText
+	KindCode
+	KindImage
+	KindVideo
+	KindAudio
+	KindArchive
+	KindDocument
+	KindBinary
+)
+
+// String returns a short description of the kind
+func (k FileKind) String() string {
+	switch k {
+	case KindText:
+		return "Text file"
+	case KindCode:
+		return "Source file"
+	case KindImage:
+		return "Image file"
+	case KindVideo:
+		return "Video file"
+	case KindAudio:
+		return "Audio file"
+	case KindArchive:
+		return "Archive"
+	case KindDocument:
+		return "Document"
+	case KindBinary:
+		return "Binary file"
+	default:
+		return "Unknown type"
+	}
+}
+
+// PreviewType represents how a file should be previewed
+type PreviewType int
+
+const (
+	PreviewText PreviewType = iota
+	PreviewImage
+	PreviewBinary
+	PreviewUnsupported
+	PreviewJSON
+	PreviewYAML
+	PreviewCSV
+	PreviewTOML
+	PreviewINI
+	PreviewMarkdown
+	PreviewArchive
+)
+
+// isValidUTF8 checks if data appears to be valid UTF-8 text
+func isValidUTF8(data []byte) bool {
+	// Check if it's valid UTF-8
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	// Check for control characters (except common ones)
+	controlCharCount := 0
+	for _, b := range data {
+		// Allow: tab (9), newline (10), carriage return (13)
+		if b < 32 && b != 9 && b != 10 && b != 13 {
+			controlCharCount++
+		}
+	}
+
+	// If more than 5% control characters, likely binary
+	return float64(controlCharCount)/float64(len(data)) < 0.05
+}
+
+// FileType is the detected kind and MIME type of a file
+type FileType struct {
+	Kind     FileKind
+	MimeType string // Empty if unknown
+}
+
+// IsText reports whether the file holds human-readable text
+func (t FileType) IsText() bool {
+	return t.Kind == KindText || t.Kind == KindCode
+}
+
+// Preview returns how files of this type are previewed
+func (t FileType) Preview() PreviewType {
+	switch t.MimeType {
+	case "application/json":
+		return PreviewJSON
+	case "application/yaml":
+		return PreviewYAML
+	case "text/csv", "text/tab-separated-values":
+		return PreviewCSV
+	case "application/toml":
+		return PreviewTOML
+	case "text/x-ini":
+		return PreviewINI
+	case "text/markdown":
+		return PreviewMarkdown
+	case "application/zip", "application/x-tar", "application/gzip", "application/x-bzip2":
+		return PreviewArchive
+	}
+
+	switch t.Kind {
+	case KindText, KindCode, KindUnknown:
+		// Unknown files are tried as text, which is validated while reading
+		return PreviewText
+	case KindImage:
+		if decodableImages[t.MimeType] {
+			return PreviewImage
+		}
+		if t.MimeType == "image/svg+xml" {
+			return PreviewText
+		}
+		return PreviewBinary
+	default:
+		return PreviewBinary
+	}
+}
+
+// decodableImages are the image formats with a registered decoder
+var decodableImages = map[string]bool{
+	"image/png": true, "image/jpeg": true, "image/gif": true,
+	"image/bmp": true, "image/webp": true,
+}
+
+// extensionTypes maps lowercase extensions to the type they usually hold
+var extensionTypes = map[string]FileType{
+	// Text
+	".txt": {KindText, "text/plain"}, ".md": {KindText, "text/markdown"},
+	".log": {KindText, "text/plain"}, ".conf": {KindText, "text/plain"},
+	".cfg": {KindText, "text/plain"}, ".ini": {KindText, "text/x-ini"},
+	".csv": {KindText, "text/csv"}, ".tsv": {KindText, "text/tab-separated-values"},
+	".env": {KindText, "text/plain"}, ".gitignore": {KindText, "text/plain"},
+
+	// Source code and structured text
+	".go": {KindCode, "text/x-go"}, ".js": {KindCode, "text/javascript"},
+	".ts": {KindCode, "text/typescript"}, ".py": {KindCode, "text/x-python"},
+	".java": {KindCode, "text/x-java"}, ".c": {KindCode, "text/x-c"},
+	".cpp": {KindCode, "text/x-c++"}, ".h": {KindCode, "text/x-c"},
+	".hpp": {KindCode, "text/x-c++"}, ".rs": {KindCode, "text/x-rust"},
+	".rb": {KindCode, "text/x-ruby"}, ".php": {KindCode, "text/x-php"},
+	".sh": {KindCode, "text/x-shellscript"}, ".bash": {KindCode, "text/x-shellscript"},
+	".zsh": {KindCode, "text/x-shellscript"}, ".fish": {KindCode, "text/x-shellscript"},
+	".json": {KindCode, "application/json"}, ".xml": {KindCode, "application/xml"},
+	".yaml": {KindCode, "application/yaml"}, ".yml": {KindCode, "application/yaml"},
+	".toml": {KindCode, "application/toml"}, ".html": {KindCode, "text/html"},
+	".css": {KindCode, "text/css"}, ".scss": {KindCode, "text/x-scss"},
+	".sass": {KindCode, "text/x-sass"}, ".sql": {KindCode, "application/sql"},
+	".dockerfile": {KindCode, "text/x-dockerfile"},
+
+	// Images
+	".png": {KindImage, "image/png"}, ".jpg": {KindImage, "image/jpeg"},
+	".jpeg": {KindImage, "image/jpeg"}, ".gif": {KindImage, "image/gif"},
+	".bmp": {KindImage, "image/bmp"}, ".webp": {KindImage, "image/webp"},
+	".svg": {KindImage, "image/svg+xml"},
+
+	// Video and audio
+	".mp4": {KindVideo, "video/mp4"}, ".mkv": {KindVideo, "video/x-matroska"},
+	".avi": {KindVideo, "video/x-msvideo"}, ".webm": {KindVideo, "video/webm"},
+	".mov": {KindVideo, "video/quicktime"},
+	".mp3": {KindAudio, "audio/mpeg"}, ".wav": {KindAudio, "audio/wav"},
+	".flac": {KindAudio, "audio/flac"}, ".ogg": {KindAudio, "audio/ogg"},
+
+	// Archives
+	".zip": {KindArchive, "application/zip"}, ".tar": {KindArchive, "application/x-tar"},
+	".gz": {KindArchive, "application/gzip"}, ".tgz": {KindArchive, "application/gzip"},
+	".bz2": {KindArchive, "application/x-bzip2"}, ".xz": {KindArchive, "application/x-xz"},
+	".7z": {KindArchive, "application/x-7z-compressed"}, ".rar": {KindArchive, "application/vnd.rar"},
+
+	// Documents
+	".pdf": {KindDocument, "application/pdf"}, ".doc": {KindDocument, "application/msword"},
+	".docx": {KindDocument, "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+	".xls":  {KindDocument, "application/vnd.ms-excel"},
+	".xlsx": {KindDocument, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"},
+	".ppt":  {KindDocument, "application/vnd.ms-powerpoint"},
+	".pptx": {KindDocument, "application/vnd.openxmlformats-officedocument.presentationml.presentation"},
+
+	// Other binaries
+	".exe": {KindBinary, "application/vnd.microsoft.portable-executable"},
+	".dll": {KindBinary, "application/vnd.microsoft.portable-executable"},
+	".so":  {KindBinary, "application/x-sharedlib"}, ".dylib": {KindBinary, "application/x-mach-binary"},
+	".bin": {KindBinary, "application/octet-stream"}, ".dat": {KindBinary, "application/octet-stream"},
+	".db": {KindBinary, "application/vnd.sqlite3"}, ".sqlite": {KindBinary, "application/vnd.sqlite3"},
+}
+
+// mimeTypes maps MIME types to the type of the extensions holding them. Extensions
+// are visited in order, so a MIME type shared by several extensions always maps the same way.
+var mimeTypes = func() map[string]FileType {
+	exts := make([]string, 0, len(extensionTypes))
+	for ext := range extensionTypes {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	types := make(map[string]FileType, len(exts))
+	for _, ext := range exts {
+		t := extensionTypes[ext]
+		if _, ok := types[t.MimeType]; !ok {
+			types[t.MimeType] = t
+		}
+	}
+	return types
+}()
+
+// mimeAliases maps nonstandard MIME types, as returned by http.DetectContentType, to their common name
+var mimeAliases = map[string]string{
+	"application/x-gzip":           "application/gzip",
+	"application/x-zip-compressed": "application/zip",
+	"application/x-rar-compressed": "application/vnd.rar",
+	"application/ogg":              "audio/ogg",
+	"audio/wave":                   "audio/wav",
+	"video/avi":                    "video/x-msvideo",
+}
+
+// nameTypes maps well-known file names without extension to their type
+var nameTypes = map[string]FileType{
+	"dockerfile": {KindCode, "text/x-dockerfile"},
+	"makefile":   {KindCode, "text/x-makefile"},
+	"readme":     {KindText, "text/plain"},
+	"license":    {KindText, "text/plain"},
+}
+
+// magicTypes are formats recognized by their leading bytes that http.DetectContentType misses
+var magicTypes = []struct {
+	offset int
+	magic  []byte
+	typ    FileType
+}{
+	{0, []byte("7z\xbc\xaf\x27\x1c"), FileType{KindArchive, "application/x-7z-compressed"}},
+	{0, []byte("BZh"), FileType{KindArchive, "application/x-bzip2"}},
+	{0, []byte("\xfd7zXZ\x00"), FileType{KindArchive, "application/x-xz"}},
+	{257, []byte("ustar"), FileType{KindArchive, "application/x-tar"}},
+	{0, []byte("SQLite format 3\x00"), FileType{KindBinary, "application/vnd.sqlite3"}},
+	{0, []byte("\x7fELF"), FileType{KindBinary, "application/x-executable"}},
+	{0, []byte("\x1a\x45\xdf\xa3"), FileType{KindVideo, "video/x-matroska"}},
+	{0, []byte("fLaC"), FileType{KindAudio, "audio/flac"}},
+	{0, []byte("ID3"), FileType{KindAudio, "audio/mpeg"}},
+}
+
+// detectExtension returns the type suggested by the name of a file
+func detectExtension(name string) (FileType, bool) {
+	if t, ok := extensionTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		return t, true
+	}
+	t, ok := nameTypes[strings.ToLower(name)]
+	return t, ok
+}
+
+// detectMimeType classifies a MIME type such as the content type stored in the VFS metadata.
+// Generic types like application/octet-stream do not count as a detection.
+func detectMimeType(contentType string) (FileType, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" || mediaType == "application/octet-stream" {
+		return FileType{}, false
+	}
+	if alias, ok := mimeAliases[mediaType]; ok {
+		mediaType = alias
+	}
+
+	// Prefer the kind of a known extension with the same MIME type
+	if t, ok := mimeTypes[mediaType]; ok {
+		return t, true
+	}
+
+	kind := KindBinary
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		kind = KindText
+	case strings.HasPrefix(mediaType, "image/"):
+		kind = KindImage
+	case strings.HasPrefix(mediaType, "video/"):
+		kind = KindVideo
+	case strings.HasPrefix(mediaType, "audio/"):
+		kind = KindAudio
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		kind = KindCode
+	}
+	return FileType{Kind: kind, MimeType: mediaType}, true
+}
+
+// sniffContent recognizes the format of a file from its leading bytes.
+// Anything that is not a known format is reported as plain text or binary.
+func sniffContent(head []byte) FileType {
+	for _, m := range magicTypes {
+		if len(head) >= m.offset+len(m.magic) && bytes.Equal(head[m.offset:m.offset+len(m.magic)], m.magic) {
+			return m.typ
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	switch {
+	case mediaType == "text/plain", mediaType == "application/octet-stream":
+		// Fall through to the text heuristic shared with the text preview
+	case strings.HasPrefix(mediaType, "text/"):
+		return FileType{Kind: KindCode, MimeType: mediaType}
+	default:
+		if t, ok := detectMimeType(mediaType); ok {
+			return t
+		}
+	}
+
+	if isValidUTF8(trimPartialRune(head)) {
+		return FileType{Kind: KindText, MimeType: "text/plain"}
+	}
+	return FileType{Kind: KindBinary, MimeType: "application/octet-stream"}
+}
+
+// DetectFileType combines the stored content type, the leading bytes of a file and its name.
+// Formats recognized by their magic bytes always win, so wrongly named files are handled.
+// For plain text and unknown binary data the stored type and the extension tell the details.
+// Without head only the metadata and the name are used.
+func DetectFileType(name string, contentType data.ContentType, head []byte) FileType {
+	byMeta, metaOK := detectMimeType(string(contentType))
+	byName, nameOK := detectExtension(name)
+
+	if len(head) == 0 {
+		switch {
+		case metaOK:
+			return byMeta
+		case nameOK:
+			return byName
+		default:
+			return FileType{Kind: KindUnknown}
+		}
+	}
+
+	sniffed := sniffContent(head)
+	switch {
+	case sniffed.Kind == KindText:
+		// Text may be any kind of source or markup, and SVG images are text as well
+		if metaOK && (byMeta.IsText() || byMeta.MimeType == "image/svg+xml") {
+			return byMeta
+		}
+		if nameOK && (byName.IsText() || byName.MimeType == "image/svg+xml") {
+			return byName
+		}
+	case sniffed.Kind == KindBinary:
+		// Binary data is only trusted to be the stored or named type if that is binary too
+		if metaOK && !byMeta.IsText() {
+			return byMeta
+		}
+		if nameOK && !byName.IsText() {
+			return byName
+		}
+	}
+	return sniffed
+}
+
+// FileType returns the type of the entry, using its sniffed content if available
+func (e *Entry) FileType() FileType {
+	if e.fileType != nil {
+		return *e.fileType
+	}
+	return DetectFileType(e.Name, e.MimeType, nil)
+}
+
+// SniffFileType detects the type of entry from its metadata and its first bytes,
+// which are read with a single range read, and remembers the result in the entry
+func (a *Adapter) SniffFileType(entry *Entry) FileType {
+	if entry.IsDir || entry.Size == 0 {
+		return entry.FileType()
+	}
+
+	size := entry.Size
+	if size > sniffSize {
+		size = sniffSize
+	}
+
+	if t, ok := a.sniffs.get(entry.Path, entry.Size, entry.ModTime); ok {
+		entry.fileType = &t
+		return t
+	}
+
+	head, err := a.readHead(entry.Path, size)
+	if err != nil {
+		return entry.FileType()
+	}
+
+	t := DetectFileType(entry.Name, entry.MimeType, head)
+	entry.fileType = &t
+	a.sniffs.put(entry.Path, entry.Size, entry.ModTime, t)
+	return t
+}
+
+// SniffEntries detects the type of the first maxSniffEntries files of a listing
+func (a *Adapter) SniffEntries(entries []*Entry) {
+	sniffed := 0
+	for _, entry := range entries {
+		if sniffed >= maxSniffEntries || a.ctx.Err() != nil {
+			return
+		}
+		// Members of compressed archives can only be reached by decompressing
+		// everything before them, so they are recognized by name alone
+		if entry.IsDir || entry.Size == 0 || entry.inArchive {
+			continue
+		}
+
+		// Types sniffed by an earlier listing cost no read and do not count
+		if t, ok := a.sniffs.get(entry.Path, entry.Size, entry.ModTime); ok {
+			entry.fileType = &t
+			continue
+		}
+
+		a.SniffFileType(entry)
+		sniffed++
+	}
+}
+
+// sniffCache remembers sniffed file types across listings, shared by all copies of an adapter
+type sniffCache struct {
+	mu    sync.Mutex
+	types map[string]sniffedType
+}
+
+// sniffedType is the type of a file as it was when sniffed
+type sniffedType struct {
+	size    int64
+	modTime time.Time
+	typ     FileType
+}
+
+// newSniffCache creates an empty cache
+func newSniffCache() *sniffCache {
+	return &sniffCache{types: make(map[string]sniffedType)}
+}
+
+// get returns the type sniffed for the file at path if it has not changed since
+func (c *sniffCache) get(path string, size int64, modTime time.Time) (FileType, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sniffed, ok := c.types[path]
+	if !ok || sniffed.size != size || !sniffed.modTime.Equal(modTime) {
+		return FileType{}, false
+	}
+	return sniffed.typ, true
+}
+
+// put remembers the type sniffed for the file at path, dropping an older one if the cache is full
+func (c *sniffCache) put(path string, size int64, modTime time.Time, typ FileType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.types[path]; !ok && len(c.types) >= maxCachedSniffs {
+		for key := range c.types {
+			delete(c.types, key)
+			break
+		}
+	}
+	c.types[path] = sniffedType{size: size, modTime: modTime, typ: typ}
+}
@@ -0,0 +1,67 @@
+package vfsadapter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Move renames or moves a file or directory tree from src to dst.
+// The vfs offers no rename yet, so the entry is copied and the source removed afterwards,
+// even within a single mount. Native renames depend on a rename operation in the vfs.
+func (a *Adapter) Move(src, dst string, fn ProgressFunc) error {
+	src = filepath.Clean(src)
+	dst = filepath.Clean(dst)
+
+	if src == dst {
+		return nil
+	}
+	if strings.HasPrefix(dst, src+"/") {
+		return fmt.Errorf("cannot move '%s' into itself", src)
+	}
+
+	if a.inArchive(src) || a.inArchive(dst) {
+		return errArchiveReadOnly
+	}
+
+	srcEntry, err := a.Stat(src)
+	if err != nil {
+		return err
+	}
+	if srcEntry.Mode.IsMount() {
+		return fmt.Errorf("cannot move mount point '%s'", src)
+	}
+	if a.Exists(dst) {
+		return fmt.Errorf("destination already exists: %s", dst)
+	}
+
+	if err := a.Copy(src, dst, fn); err != nil {
+		// The source is untouched at this point, so removing the partial copy is safe.
+		// The rollback must still run if the copy failed because ctx was canceled.
+		rollback := a.WithContext(context.WithoutCancel(a.ctx))
+		if rollbackErr := rollback.Remove(dst, srcEntry.IsDir); rollbackErr != nil {
+			return fmt.Errorf("failed to move '%s': %v (rollback failed: %v)", src, err, rollbackErr)
+		}
+		return fmt.Errorf("failed to move '%s': %w", src, err)
+	}
+
+	// The copy is complete, so the destination is kept even if removing the source
+	// fails partway. Deleting it could otherwise lose data that was already removed.
+	if err := a.Remove(src, srcEntry.IsDir); err != nil {
+		return fmt.Errorf("copied to '%s' but failed to remove source '%s': %w", dst, src, err)
+	}
+
+	return nil
+}
+
+// Remove deletes path if it exists
+func (a *Adapter) Remove(path string, isDir bool) error {
+	if !a.Exists(path) {
+		return nil
+	}
+	if isDir {
+		return a.DeleteRecursive(path)
+	}
+	return a.Delete(path, false)
+}
@@ -0,0 +1,166 @@
+package vfsadapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		src   string
+		dst   string
+		want  map[string]string // Files expected after the move and their content
+	}{
+		{
+			name:  "file within a mount",
+			files: map[string]string{"/a.txt": "hello"},
+			src:   "/a.txt",
+			dst:   "/b.txt",
+			want:  map[string]string{"/b.txt": "hello"},
+		},
+		{
+			name:  "file across mounts",
+			files: map[string]string{"/a.txt": "hello"},
+			src:   "/a.txt",
+			dst:   "/other/a.txt",
+			want:  map[string]string{"/other/a.txt": "hello"},
+		},
+		{
+			name: "directory across mounts",
+			files: map[string]string{
+				"/src/":          "",
+				"/src/a.txt":     "alpha",
+				"/src/sub/":      "",
+				"/src/sub/b.txt": "beta",
+			},
+			src: "/src",
+			dst: "/other/dst",
+			want: map[string]string{
+				"/other/dst/a.txt":     "alpha",
+				"/other/dst/sub/b.txt": "beta",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newTestAdapter(t, tt.files)
+
+			if err := adapter.Move(tt.src, tt.dst, nil); err != nil {
+				t.Fatalf("Move(%q, %q) = %v, want nil", tt.src, tt.dst, err)
+			}
+
+			if adapter.Exists(tt.src) {
+				t.Fatalf("Move(%q, %q) kept the source", tt.src, tt.dst)
+			}
+			for path, want := range tt.want {
+				if got := readTestFile(t, adapter, path); got != want {
+					t.Fatalf("%s = %q after move, want %q", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		dst  string
+		want string
+	}{
+		{"destination exists", "/a.txt", "/b.txt", "destination already exists"},
+		{"into itself", "/src", "/src/sub/moved", "into itself"},
+		{"mount point", "/other", "/moved", "cannot move mount point"},
+		{"missing source", "/missing", "/moved", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := newTestAdapter(t, map[string]string{
+				"/a.txt":     "alpha",
+				"/b.txt":     "beta",
+				"/src/":      "",
+				"/src/sub/":  "",
+				"/src/c.txt": "gamma",
+			})
+
+			err := adapter.Move(tt.src, tt.dst, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Move(%q, %q) = %v, want error containing %q", tt.src, tt.dst, err, tt.want)
+			}
+
+			// A failed move leaves both sides as they were
+			if got := readTestFile(t, adapter, "/b.txt"); got != "beta" {
+				t.Fatalf("/b.txt = %q after failed move, want %q", got, "beta")
+			}
+			if !adapter.Exists("/src/c.txt") || adapter.Exists("/moved") {
+				t.Fatalf("Move(%q, %q) changed the tree after failing", tt.src, tt.dst)
+			}
+		})
+	}
+}
+
+func TestMoveSamePath(t *testing.T) {
+	adapter := newTestAdapter(t, map[string]string{"/a.txt": "hello"})
+
+	if err := adapter.Move("/a.txt", "/a.txt/", nil); err != nil {
+		t.Fatalf("Move() = %v, want nil", err)
+	}
+	if got := readTestFile(t, adapter, "/a.txt"); got != "hello" {
+		t.Fatalf("/a.txt = %q after move onto itself, want %q", got, "hello")
+	}
+}
+
+func TestMoveCanceledRollsBack(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		src   string
+		dst   string
+	}{
+		{
+			name:  "file",
+			files: map[string]string{"/large.bin": strings.Repeat("x", copyBufferSize*3)},
+			src:   "/large.bin",
+			dst:   "/other/large.bin",
+		},
+		{
+			name:  "directory",
+			files: map[string]string{"/src/": "", "/src/a.txt": "alpha", "/src/b.txt": "beta"},
+			src:   "/src",
+			dst:   "/other/src",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			adapter := newTestAdapter(t, tt.files)
+
+			// Cancel once the first chunk has been written to the destination
+			err := adapter.WithContext(ctx).Move(tt.src, tt.dst, func(CopyProgress) { cancel() })
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Move(%q, %q) = %v, want %v", tt.src, tt.dst, err, context.Canceled)
+			}
+
+			if adapter.Exists(tt.dst) {
+				t.Fatalf("Move(%q, %q) left the partial destination", tt.src, tt.dst)
+			}
+			for path, want := range tt.files {
+				if strings.HasSuffix(path, "/") {
+					continue
+				}
+				if got := readTestFile(t, adapter, path); got != want {
+					t.Fatalf("%s has %d bytes after canceled move, want %d", path, len(got), len(want))
+				}
+			}
+		})
+	}
+}
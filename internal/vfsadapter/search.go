@@ -0,0 +1,248 @@
+package vfsadapter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// searchSniffSize is how much of a file is checked before it is treated as text
+	searchSniffSize = 8 * 1024
+	// searchMaxLine is the longest line that is matched, longer lines skip the file
+	searchMaxLine = 1024 * 1024
+	// searchMaxSnippet limits how much of a matching line is kept
+	searchMaxSnippet = 200
+)
+
+// errSearchLimit stops the walk once enough matches were found
+var errSearchLimit = errors.New("search limit reached")
+
+// SearchOptions controls how file contents are matched
+type SearchOptions struct {
+	Pattern    string
+	Regex      bool // Treat Pattern as regular expression instead of literal
+	IgnoreCase bool
+	MaxResults int // Stop after this many matches, 0 for no limit
+	MaxPerFile int // Stop reading a file after this many matches, 0 for no limit
+}
+
+// SearchMatch is a single matching line
+type SearchMatch struct {
+	Path string
+	Line int
+	Text string
+}
+
+// String formats the match as path:line:snippet
+func (m SearchMatch) String() string {
+	return fmt.Sprintf("%s:%d:%s", m.Path, m.Line, m.Text)
+}
+
+// SearchSummary reports what a search has looked at
+type SearchSummary struct {
+	Files   int // Text files that were searched
+	Skipped int // Binary or unreadable files
+	Matches int
+}
+
+// Validate reports an empty or invalid pattern before a search is started
+func (o SearchOptions) Validate() error {
+	_, err := o.matcher()
+	return err
+}
+
+// matcher compiles the options into a line matcher
+func (o SearchOptions) matcher() (func(string) bool, error) {
+	if o.Pattern == "" {
+		return nil, fmt.Errorf("search pattern is empty")
+	}
+
+	if o.Regex {
+		expr := o.Pattern
+		if o.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		return re.MatchString, nil
+	}
+
+	if o.IgnoreCase {
+		pattern := strings.ToLower(o.Pattern)
+		return func(line string) bool {
+			return strings.Contains(strings.ToLower(line), pattern)
+		}, nil
+	}
+
+	return func(line string) bool {
+		return strings.Contains(line, o.Pattern)
+	}, nil
+}
+
+// Search walks root and calls fn for every matching line in a text file.
+// Binary files are skipped using the same heuristic as the text preview.
+// The optional report function receives the number of searched files.
+func (a *Adapter) Search(root string, opts SearchOptions, report ProgressFunc, fn func(SearchMatch)) (SearchSummary, error) {
+	summary := SearchSummary{}
+
+	match, err := opts.matcher()
+	if err != nil {
+		return summary, err
+	}
+
+	s := &search{
+		adapter: a,
+		opts:    opts,
+		match:   match,
+		report:  report,
+		fn:      fn,
+		summary: &summary,
+	}
+
+	// The root has no metadata of its own, but is always a directory
+	isDir := root == "/"
+	if !isDir {
+		entry, err := a.Stat(root)
+		if err != nil {
+			return summary, err
+		}
+		isDir = entry.IsDir
+	}
+
+	if isDir {
+		err = s.searchDirectory(root)
+	} else {
+		err = s.searchFile(root)
+	}
+
+	if errors.Is(err, errSearchLimit) {
+		err = nil
+	}
+	return summary, err
+}
+
+// search holds the state of a single Search call
+type search struct {
+	adapter *Adapter
+	opts    SearchOptions
+	match   func(string) bool
+	report  ProgressFunc
+	fn      func(SearchMatch)
+	summary *SearchSummary
+}
+
+// searchDirectory searches every file below dir
+func (s *search) searchDirectory(dir string) error {
+	entries, err := s.adapter.ListDirectory(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := s.adapter.ctx.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir {
+			err = s.searchDirectory(entry.Path)
+		} else {
+			err = s.searchFile(entry.Path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// searchFile matches every line of a single file
+func (s *search) searchFile(path string) error {
+	reader, err := s.adapter.StreamFile(path)
+	if err != nil {
+		s.summary.Skipped++
+		return nil
+	}
+	defer reader.Close()
+
+	buffered := bufio.NewReaderSize(reader, searchSniffSize)
+	head, err := buffered.Peek(searchSniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.summary.Skipped++
+		return nil
+	}
+	if len(head) == 0 {
+		return nil
+	}
+	if !isValidUTF8(trimPartialRune(head)) {
+		s.summary.Skipped++
+		return nil
+	}
+
+	s.summary.Files++
+	if s.report != nil {
+		s.report(CopyProgress{Files: s.summary.Files, Path: path})
+	}
+
+	scanner := bufio.NewScanner(buffered)
+	scanner.Buffer(make([]byte, 0, 64*1024), searchMaxLine)
+
+	line, matches := 0, 0
+	for scanner.Scan() {
+		line++
+
+		text := scanner.Text()
+		if !s.match(text) {
+			continue
+		}
+
+		matches++
+		s.summary.Matches++
+		s.fn(SearchMatch{Path: path, Line: line, Text: snippet(text)})
+
+		if s.opts.MaxResults > 0 && s.summary.Matches >= s.opts.MaxResults {
+			return errSearchLimit
+		}
+		if s.opts.MaxPerFile > 0 && matches >= s.opts.MaxPerFile {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil
+		}
+		return fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut off at the end of data
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
+
+// snippet trims a matching line for display
+func snippet(line string) string {
+	line = strings.TrimSpace(line)
+	if utf8.RuneCountInString(line) > searchMaxSnippet {
+		line = string([]rune(line)[:searchMaxSnippet]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,134 @@
+package vfsadapter
+
+import (
+	"sort"
+)
+
+// UsageOptions controls how disk usage is collected
+type UsageOptions struct {
+	OneFileSystem bool // Do not descend into mount points below the root
+}
+
+// UsageNode is the accumulated size of an entry and everything below it
+type UsageNode struct {
+	Entry    *Entry
+	Size     int64
+	Files    int
+	Err      error        // Set if the directory could not be listed
+	Parent   *UsageNode   // Nil for the root of the walk
+	Children []*UsageNode // Sorted by size, largest first
+}
+
+// Remove detaches the node from its parent and subtracts its size from every ancestor
+func (n *UsageNode) Remove() {
+	parent := n.Parent
+	if parent == nil {
+		return
+	}
+
+	for i, child := range parent.Children {
+		if child == n {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			break
+		}
+	}
+
+	for p := parent; p != nil; p = p.Parent {
+		p.Size -= n.Size
+		p.Files -= n.Files
+	}
+	n.Parent = nil
+}
+
+// DiskUsage walks root and sums the size of every file below it.
+// Directories that cannot be listed are kept with their error instead of failing the walk.
+// The optional report function receives the number of files and bytes counted so far.
+func (a *Adapter) DiskUsage(root string, opts UsageOptions, report ProgressFunc) (*UsageNode, error) {
+	// The root has no metadata of its own, but is always a directory
+	entry := &Entry{Name: "/", Path: "/", IsDir: true}
+	if root != "/" {
+		var err error
+		if entry, err = a.Stat(root); err != nil {
+			return nil, err
+		}
+	}
+
+	node := &UsageNode{Entry: entry}
+	if !entry.IsDir {
+		node.Size = entry.Size
+		node.Files = 1
+		return node, nil
+	}
+
+	// The root itself must be listable, only errors further down are recorded
+	entries, err := a.ListDirectory(root)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &usageWalk{
+		adapter: a,
+		opts:    opts,
+		report:  report,
+	}
+	if err := u.add(node, entries); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// usageWalk holds the state of a single DiskUsage call
+type usageWalk struct {
+	adapter  *Adapter
+	opts     UsageOptions
+	report   ProgressFunc
+	progress CopyProgress
+}
+
+// walk lists dir and accumulates the size of its children
+func (u *usageWalk) walk(node *UsageNode) error {
+	entries, err := u.adapter.ListDirectory(node.Entry.Path)
+	if err != nil {
+		node.Err = err
+		return nil
+	}
+	return u.add(node, entries)
+}
+
+// add accumulates entries as children of node, descending into directories
+func (u *usageWalk) add(node *UsageNode, entries []*Entry) error {
+	for _, entry := range entries {
+		if err := u.adapter.ctx.Err(); err != nil {
+			return err
+		}
+
+		child := &UsageNode{Entry: entry, Parent: node}
+		if entry.IsDir {
+			if !entry.Mode.IsMount() || !u.opts.OneFileSystem {
+				if err := u.walk(child); err != nil {
+					return err
+				}
+			}
+		} else {
+			child.Size = entry.Size
+			child.Files = 1
+
+			u.progress.Files++
+			u.progress.Bytes += entry.Size
+			u.progress.Path = entry.Path
+			if u.report != nil {
+				u.report(u.progress)
+			}
+		}
+
+		node.Size += child.Size
+		node.Files += child.Files
+		node.Children = append(node.Children, child)
+	}
+
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		return node.Children[i].Size > node.Children[j].Size
+	})
+	return nil
+}